@@ -91,8 +91,13 @@ func main() {
 		}()
 	}
 
+	for _, deprecation := range cfg.Collectors.Deprecations {
+		logger.Warn("Deprecated collector setting", zap.String("setting", deprecation))
+	}
+
 	logger.Info("Starting SC metrics agent",
 		zap.Duration("collection_interval", cfg.CollectionInterval),
+		zap.Duration("tick_interval", cfg.TickInterval()),
 		zap.String("metadata_service_endpoint", cfg.MetadataServiceEndpoint),
 		zap.String("vm_id", cfg.VMID),
 		zap.Any("collectors", cfg.Collectors),
@@ -107,7 +112,12 @@ func main() {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	// Initialize components
-	systemCollector, err := collector.NewSystemCollector(cfg.Collectors, logger)
+	hostPaths := collector.Paths{
+		ProcPath: cfg.ProcfsPath,
+		SysPath:  cfg.SysfsPath,
+		RootPath: cfg.RootfsPath,
+	}
+	systemCollector, err := collector.NewSystemCollector(cfg.Collectors, hostPaths, logger)
 	if err != nil {
 		logger.Fatal("Failed to create system collector", zap.Error(err))
 	}
@@ -138,7 +148,7 @@ func main() {
 	)
 
 	// Start collection loop
-	ticker := time.NewTicker(cfg.CollectionInterval)
+	ticker := time.NewTicker(cfg.TickInterval())
 	defer ticker.Stop()
 
 	logger.Info("Agent started successfully")
@@ -69,13 +69,22 @@ type Response struct {
 
 // DiagnosticPayload represents agent health information
 type DiagnosticPayload struct {
-	AgentID         string                 `json:"agent_id"`
-	Timestamp       int64                  `json:"timestamp"`
-	Status          string                 `json:"status"`
-	LastError       string                 `json:"last_error,omitempty"`
-	MetricsCount    int                    `json:"metrics_count"`
-	CollectorStatus map[string]bool        `json:"collector_status"`
-	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	AgentID          string                      `json:"agent_id"`
+	Timestamp        int64                       `json:"timestamp"`
+	Status           string                      `json:"status"`
+	LastError        string                      `json:"last_error,omitempty"`
+	MetricsCount     int                         `json:"metrics_count"`
+	CollectorStatus  map[string]bool             `json:"collector_status"`
+	CollectorDetails map[string]CollectorDetails `json:"collector_details,omitempty"`
+	Metadata         map[string]interface{}      `json:"metadata,omitempty"`
+}
+
+// CollectorDetails reports the outcome of a collector's last collection
+type CollectorDetails struct {
+	Success         bool    `json:"success"`
+	TimedOut        bool    `json:"timed_out"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	LastError       string  `json:"last_error,omitempty"`
 }
 
 // HeartbeatRequest represents the heartbeat payload
@@ -12,7 +12,7 @@ import (
 // MetricWriter defines the interface for writing metrics to an ingestor
 type MetricWriter interface {
 	WriteMetrics(ctx context.Context, metrics []aggregate.MetricWithValue, authToken string) error
-	WriteDiagnostics(ctx context.Context, agentID string, status string, lastError string, collectorStatus map[string]bool, authToken string) error
+	WriteDiagnostics(ctx context.Context, agentID string, status string, lastError string, collectorStatus map[string]bool, collectorDetails map[string]CollectorDetails, authToken string) error
 	SendHeartbeat(ctx context.Context, authToken string, version string) error
 	Close() error
 }
@@ -68,16 +68,17 @@ func (mw *metricWriter) WriteMetrics(ctx context.Context, metrics []aggregate.Me
 }
 
 // WriteDiagnostics sends diagnostic information to the ingestor
-func (mw *metricWriter) WriteDiagnostics(ctx context.Context, agentID string, status string, lastError string, collectorStatus map[string]bool, authToken string) error {
+func (mw *metricWriter) WriteDiagnostics(ctx context.Context, agentID string, status string, lastError string, collectorStatus map[string]bool, collectorDetails map[string]CollectorDetails, authToken string) error {
 	mw.logger.Debug("Writing diagnostics to ingestor", zap.String("agent_id", agentID))
 
 	diagnostics := DiagnosticPayload{
-		AgentID:         agentID,
-		Timestamp:       time.Now().UnixMilli(),
-		Status:          status,
-		LastError:       lastError,
-		MetricsCount:    0, // Will be set by caller if needed
-		CollectorStatus: collectorStatus,
+		AgentID:          agentID,
+		Timestamp:        time.Now().UnixMilli(),
+		Status:           status,
+		LastError:        lastError,
+		MetricsCount:     0, // Will be set by caller if needed
+		CollectorStatus:  collectorStatus,
+		CollectorDetails: collectorDetails,
 		Metadata: map[string]interface{}{
 			"version": "1.0",
 			"go_version": "1.24.3",
@@ -194,8 +195,8 @@ func (bmw *BatchedMetricWriter) WriteMetrics(ctx context.Context, metrics []aggr
 }
 
 // WriteDiagnostics delegates to the underlying writer
-func (bmw *BatchedMetricWriter) WriteDiagnostics(ctx context.Context, agentID string, status string, lastError string, collectorStatus map[string]bool, authToken string) error {
-	return bmw.writer.WriteDiagnostics(ctx, agentID, status, lastError, collectorStatus, authToken)
+func (bmw *BatchedMetricWriter) WriteDiagnostics(ctx context.Context, agentID string, status string, lastError string, collectorStatus map[string]bool, collectorDetails map[string]CollectorDetails, authToken string) error {
+	return bmw.writer.WriteDiagnostics(ctx, agentID, status, lastError, collectorStatus, collectorDetails, authToken)
 }
 
 // SendHeartbeat delegates to the underlying writer
@@ -0,0 +1,142 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// blockDeviceResolver maps mount sources such as /dev/mapper/vg-root or
+// /dev/disk/by-uuid/<uuid> to kernel block device names (dm-0, vda1) and
+// their device-mapper metadata
+type blockDeviceResolver struct {
+	rootPath string
+	sysPath  string
+}
+
+// blockDevices is a snapshot of the device-mapper devices present on the host
+type blockDevices struct {
+	rootPath  string
+	dmNames   map[string]string // kernel name -> device-mapper name
+	dmDevices map[string]string // device-mapper name -> kernel name
+	lvNames   map[string]string // kernel name -> logical volume name, LVM devices only
+}
+
+// maxSymlinks bounds the links followed to resolve a mount source, guarding
+// against symlink loops
+const maxSymlinks = 40
+
+// newBlockDeviceResolver creates a resolver for the host whose root
+// filesystem, holding /dev, is at rootPath
+func newBlockDeviceResolver(rootPath, sysPath string) *blockDeviceResolver {
+	return &blockDeviceResolver{rootPath: rootPath, sysPath: sysPath}
+}
+
+// load reads the device-mapper names from /sys/block/*/dm. Hosts without
+// device-mapper simply yield an empty snapshot.
+func (r *blockDeviceResolver) load() *blockDevices {
+	devices := &blockDevices{
+		rootPath:  r.rootPath,
+		dmNames:   make(map[string]string),
+		dmDevices: make(map[string]string),
+		lvNames:   make(map[string]string),
+	}
+
+	dmDirs, err := filepath.Glob(filepath.Join(r.sysPath, "block", "*", "dm"))
+	if err != nil {
+		return devices
+	}
+
+	for _, dmDir := range dmDirs {
+		name, err := os.ReadFile(filepath.Join(dmDir, "name"))
+		if err != nil {
+			continue
+		}
+
+		kernelName := filepath.Base(filepath.Dir(dmDir))
+		dmName := strings.TrimSpace(string(name))
+		devices.dmNames[kernelName] = dmName
+		devices.dmDevices[dmName] = kernelName
+
+		// LVM tags its device-mapper UUIDs with an "LVM-" prefix
+		if uuid, err := os.ReadFile(filepath.Join(dmDir, "uuid")); err == nil && strings.HasPrefix(string(uuid), "LVM-") {
+			if _, lv := splitLVMName(dmName); lv != "" {
+				devices.lvNames[kernelName] = lv
+			}
+		}
+	}
+
+	return devices
+}
+
+// kernelName returns the /proc/diskstats name of the device behind a /dev mount source
+func (d *blockDevices) kernelName(source string) string {
+	relPath := strings.TrimPrefix(source, "/dev/")
+
+	// /dev/mapper/* and /dev/disk/by-*/* are symlinks to the kernel device node
+	if resolved, ok := d.resolveLink(filepath.Join("/dev", relPath)); ok {
+		return filepath.Base(resolved)
+	}
+
+	// Without a usable /dev (e.g. in a container), fall back to the sysfs names
+	if dmName, ok := strings.CutPrefix(relPath, "mapper/"); ok {
+		if kernelName, ok := d.dmDevices[dmName]; ok {
+			return kernelName
+		}
+	}
+
+	return relPath
+}
+
+// resolveLink follows the symlinks of a host path one link at a time, reading
+// each under rootPath. Absolute targets such as /dev/mapper/x -> /dev/dm-0
+// are thus resolved against the host root rather than the agent's own, which
+// differ in a container
+func (d *blockDevices) resolveLink(path string) (string, bool) {
+	for i := 0; i < maxSymlinks; i++ {
+		hostPath := filepath.Join(d.rootPath, path)
+		info, err := os.Lstat(hostPath)
+		if err != nil {
+			return "", false
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			return path, true
+		}
+
+		target, err := os.Readlink(hostPath)
+		if err != nil {
+			return "", false
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		path = filepath.Clean(target)
+	}
+
+	return "", false
+}
+
+// labels returns the device-mapper name and LVM logical volume of a kernel
+// device, both empty for plain block devices
+func (d *blockDevices) labels(kernelName string) (dmName, lv string) {
+	return d.dmNames[kernelName], d.lvNames[kernelName]
+}
+
+// splitLVMName splits a device-mapper name such as "vg--data-lv_root" into its
+// volume group and logical volume; LVM escapes hyphens within names as "--"
+func splitLVMName(dmName string) (vg, lv string) {
+	for i := 0; i < len(dmName); i++ {
+		if dmName[i] != '-' {
+			continue
+		}
+		if i+1 < len(dmName) && dmName[i+1] == '-' {
+			i++
+			continue
+		}
+
+		unescape := func(s string) string { return strings.ReplaceAll(s, "--", "-") }
+		return unescape(dmName[:i]), unescape(dmName[i+1:])
+	}
+
+	return "", ""
+}
@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeBlockDeviceTree builds /dev and /sys fixtures for an LVM root volume on
+// dm-0, a plain device-mapper volume on dm-1 and a regular partition vda1. The
+// /dev/disk/by-label and /dev/root links are absolute, as udev creates some
+func newFakeBlockDeviceTree(t *testing.T) *blockDeviceResolver {
+	t.Helper()
+
+	root := t.TempDir()
+	writeFixture(t, root, "sys/block/dm-0/dm/name", "ubuntu--vg-ubuntu--lv\n")
+	writeFixture(t, root, "sys/block/dm-0/dm/uuid", "LVM-Xk9ZqFJxQe3n0aD1\n")
+	writeFixture(t, root, "sys/block/dm-1/dm/name", "cryptdata\n")
+	writeFixture(t, root, "sys/block/dm-1/dm/uuid", "CRYPT-LUKS2-1f2e3d4c-cryptdata\n")
+	writeFixture(t, root, "sys/block/vda/vda1/partition", "1\n")
+
+	writeFixture(t, root, "dev/dm-0", "")
+	writeFixture(t, root, "dev/vda1", "")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "dev", "mapper"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "dev", "disk", "by-uuid"), 0o755))
+	require.NoError(t, os.Symlink("../dm-0", filepath.Join(root, "dev", "mapper", "ubuntu--vg-ubuntu--lv")))
+	require.NoError(t, os.Symlink("../../vda1", filepath.Join(root, "dev", "disk", "by-uuid", "0b1c5e7a-boot")))
+
+	writeFixture(t, root, "dev/dm-1", "")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "dev", "disk", "by-label"), 0o755))
+	require.NoError(t, os.Symlink("/dev/dm-1", filepath.Join(root, "dev", "disk", "by-label", "data")))
+	require.NoError(t, os.Symlink("/dev/mapper/ubuntu--vg-ubuntu--lv", filepath.Join(root, "dev", "root")))
+	require.NoError(t, os.Symlink("/dev/loop-a", filepath.Join(root, "dev", "loop-b")))
+	require.NoError(t, os.Symlink("/dev/loop-b", filepath.Join(root, "dev", "loop-a")))
+
+	return newBlockDeviceResolver(root, filepath.Join(root, "sys"))
+}
+
+func TestBlockDeviceResolver(t *testing.T) {
+	devices := newFakeBlockDeviceTree(t).load()
+
+	tests := []struct {
+		source     string
+		kernelName string
+		dmName     string
+		lv         string
+	}{
+		{"/dev/mapper/ubuntu--vg-ubuntu--lv", "dm-0", "ubuntu--vg-ubuntu--lv", "ubuntu-lv"},
+		{"/dev/disk/by-uuid/0b1c5e7a-boot", "vda1", "", ""},
+		{"/dev/vda1", "vda1", "", ""},
+		// Absolute links resolve under the root path, not the agent's /
+		{"/dev/disk/by-label/data", "dm-1", "cryptdata", ""},
+		{"/dev/root", "dm-0", "ubuntu--vg-ubuntu--lv", "ubuntu-lv"},
+		// Link loops fall back to the source name
+		{"/dev/loop-a", "loop-a", "", ""},
+		// No /dev/mapper symlink, resolved through /sys/block/*/dm/name
+		{"/dev/mapper/cryptdata", "dm-1", "cryptdata", ""},
+		{"/dev/sdb1", "sdb1", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			kernelName := devices.kernelName(tt.source)
+			assert.Equal(t, tt.kernelName, kernelName)
+
+			dmName, lv := devices.labels(kernelName)
+			assert.Equal(t, tt.dmName, dmName)
+			assert.Equal(t, tt.lv, lv)
+		})
+	}
+}
+
+func TestBlockDeviceResolverWithoutDeviceMapper(t *testing.T) {
+	devices := newBlockDeviceResolver(t.TempDir(), t.TempDir()).load()
+
+	assert.Equal(t, "vda1", devices.kernelName("/dev/vda1"))
+	dmName, lv := devices.labels("vda1")
+	assert.Empty(t, dmName)
+	assert.Empty(t, lv)
+}
+
+func TestSplitLVMName(t *testing.T) {
+	tests := []struct {
+		dmName string
+		vg     string
+		lv     string
+	}{
+		{"vg0-root", "vg0", "root"},
+		{"ubuntu--vg-ubuntu--lv", "ubuntu-vg", "ubuntu-lv"},
+		{"data--vg-lv--a--b", "data-vg", "lv-a-b"},
+		{"nohyphen", "", ""},
+	}
+
+	for _, tt := range tests {
+		vg, lv := splitLVMName(tt.dmName)
+		assert.Equal(t, tt.vg, vg, tt.dmName)
+		assert.Equal(t, tt.lv, lv, tt.dmName)
+	}
+}
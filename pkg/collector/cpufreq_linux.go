@@ -0,0 +1,105 @@
+//go:build linux
+
+package collector
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs/sysfs"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// CPU frequency scaling metrics using sysfs
+	registerCollector("cpu_freq", true, newNoOptions, func(sc *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newCPUFreqCollector(sc.sysPath, logger)
+	})
+}
+
+type cpuFreqCollector struct {
+	sysFS  sysfs.FS
+	logger *zap.Logger
+	descs  map[string]*prometheus.Desc
+}
+
+// newCPUFreqCollector creates a CPU frequency collector, returning
+// errCollectorUnavailable when the guest exposes no cpufreq directories
+func newCPUFreqCollector(sysPath string, logger *zap.Logger) (*cpuFreqCollector, error) {
+	cpufreqDirs, err := filepath.Glob(filepath.Join(sysPath, "devices", "system", "cpu", "cpu[0-9]*", "cpufreq"))
+	if err != nil {
+		return nil, err
+	}
+	if len(cpufreqDirs) == 0 {
+		return nil, fmt.Errorf("%w: no cpufreq directories under %s", errCollectorUnavailable, sysPath)
+	}
+
+	sysFS, err := sysfs.NewFS(sysPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize sysfs: %w", err)
+	}
+
+	return &cpuFreqCollector{sysFS: sysFS, logger: logger}, nil
+}
+
+func (c *cpuFreqCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = map[string]*prometheus.Desc{
+		"cur":         prometheus.NewDesc("node_cpu_frequency_hertz", "Current CPU thread frequency in hertz.", []string{"cpu"}, nil),
+		"min":         prometheus.NewDesc("node_cpu_frequency_min_hertz", "Minimum CPU thread frequency in hertz.", []string{"cpu"}, nil),
+		"max":         prometheus.NewDesc("node_cpu_frequency_max_hertz", "Maximum CPU thread frequency in hertz.", []string{"cpu"}, nil),
+		"scaling_cur": prometheus.NewDesc("node_cpu_scaling_frequency_hertz", "Current scaled CPU thread frequency in hertz.", []string{"cpu"}, nil),
+		"scaling_min": prometheus.NewDesc("node_cpu_scaling_frequency_min_hertz", "Minimum scaled CPU thread frequency in hertz.", []string{"cpu"}, nil),
+		"scaling_max": prometheus.NewDesc("node_cpu_scaling_frequency_max_hertz", "Maximum scaled CPU thread frequency in hertz.", []string{"cpu"}, nil),
+		"governor":    prometheus.NewDesc("node_cpu_scaling_governor", "Current enabled CPU frequency governor.", []string{"cpu", "governor"}, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *cpuFreqCollector) collect(ch chan<- prometheus.Metric) error {
+	stats, err := c.sysFS.SystemCpufreq()
+	if err != nil {
+		return fmt.Errorf("failed to get CPU frequency stats: %w", err)
+	}
+
+	for _, stat := range stats {
+		// CPUs without a cpufreq directory are returned as empty entries
+		if stat.Name == "" {
+			continue
+		}
+
+		// cpufreq reports frequencies in kHz
+		c.emitKHz(ch, c.descs["cur"], stat.CpuinfoCurrentFrequency, stat.Name)
+		c.emitKHz(ch, c.descs["min"], stat.CpuinfoMinimumFrequency, stat.Name)
+		c.emitKHz(ch, c.descs["max"], stat.CpuinfoMaximumFrequency, stat.Name)
+		c.emitKHz(ch, c.descs["scaling_cur"], stat.ScalingCurrentFrequency, stat.Name)
+		c.emitKHz(ch, c.descs["scaling_min"], stat.ScalingMinimumFrequency, stat.Name)
+		c.emitKHz(ch, c.descs["scaling_max"], stat.ScalingMaximumFrequency, stat.Name)
+
+		governors := strings.Fields(stat.AvailableGovernors)
+		if len(governors) == 0 && stat.Governor != "" {
+			governors = []string{stat.Governor}
+		}
+		for _, governor := range governors {
+			state := 0.0
+			if governor == stat.Governor {
+				state = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.descs["governor"], prometheus.GaugeValue, state, stat.Name, governor)
+		}
+	}
+	return nil
+}
+
+// emitKHz sends a frequency given in kHz as hertz, skipping values the kernel did not expose
+func (c *cpuFreqCollector) emitKHz(ch chan<- prometheus.Metric, desc *prometheus.Desc, khz *uint64, cpu string) {
+	if khz == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(*khz)*1000, cpu)
+}
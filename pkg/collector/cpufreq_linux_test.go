@@ -0,0 +1,58 @@
+//go:build linux
+
+package collector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestCPUFreqCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "devices/system/cpu/offline", "\n")
+	writeFixture(t, root, "devices/system/cpu/cpu1/online", "1\n")
+
+	cpufreq := map[string]string{
+		"cpuinfo_cur_freq":            "2100000",
+		"cpuinfo_min_freq":            "800000",
+		"cpuinfo_max_freq":            "3000000",
+		"scaling_cur_freq":            "1800000",
+		"scaling_min_freq":            "800000",
+		"scaling_max_freq":            "2000000",
+		"scaling_available_governors": "performance powersave",
+		"scaling_driver":              "intel_pstate",
+		"scaling_governor":            "powersave",
+		"related_cpus":                "0",
+		"scaling_setspeed":            "<unsupported>",
+	}
+	for name, value := range cpufreq {
+		writeFixture(t, root, "devices/system/cpu/cpu0/cpufreq/"+name, value+"\n")
+	}
+
+	c, err := newCPUFreqCollector(root, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	families := gatherFromCollector(t, c)
+
+	cpu0 := map[string]string{"cpu": "0"}
+	assert.Equal(t, 2.1e9, metricValue(t, families["node_cpu_frequency_hertz"], cpu0))
+	assert.Equal(t, 3e9, metricValue(t, families["node_cpu_frequency_max_hertz"], cpu0))
+	assert.Equal(t, 1.8e9, metricValue(t, families["node_cpu_scaling_frequency_hertz"], cpu0))
+	assert.Equal(t, 8e8, metricValue(t, families["node_cpu_scaling_frequency_min_hertz"], cpu0))
+	assert.Equal(t, 2e9, metricValue(t, families["node_cpu_scaling_frequency_max_hertz"], cpu0))
+	assert.Equal(t, float64(1), metricValue(t, families["node_cpu_scaling_governor"], map[string]string{"cpu": "0", "governor": "powersave"}))
+	assert.Equal(t, float64(0), metricValue(t, families["node_cpu_scaling_governor"], map[string]string{"cpu": "0", "governor": "performance"}))
+	assert.Len(t, families["node_cpu_frequency_hertz"].Metric, 1, "CPUs without cpufreq are not reported")
+}
+
+func TestCPUFreqCollectorUnavailable(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "devices/system/cpu/cpu0/online", "1\n")
+
+	_, err := newCPUFreqCollector(root, zaptest.NewLogger(t))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errCollectorUnavailable))
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// cpufreq is only exposed by Linux sysfs, so the collector is registered
+	// for configuration but reports itself as unavailable
+	registerCollector("cpu_freq", true, newNoOptions, func(_ *SystemCollector, _ config.CollectorOptions, _ *zap.Logger) (metricsCollector, error) {
+		return nil, fmt.Errorf("%w: cpufreq is only supported on Linux", errCollectorUnavailable)
+	})
+}
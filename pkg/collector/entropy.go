@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// Kernel entropy pool metrics from /proc/sys/kernel/random
+	registerCollector("entropy", true, newNoOptions, func(sc *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newEntropyCollector(sc.procPath, logger), nil
+	})
+}
+
+type entropyCollector struct {
+	procPath string
+	logger   *zap.Logger
+	descs    map[string]*prometheus.Desc
+}
+
+func newEntropyCollector(procPath string, logger *zap.Logger) *entropyCollector {
+	return &entropyCollector{procPath: procPath, logger: logger}
+}
+
+func (c *entropyCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = map[string]*prometheus.Desc{
+		"available": prometheus.NewDesc("node_entropy_available_bits", "Bits of available entropy.", nil, nil),
+		"pool_size": prometheus.NewDesc("node_entropy_pool_size_bits", "Bits of entropy pool.", nil, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *entropyCollector) collect(ch chan<- prometheus.Metric) error {
+	randomDir := filepath.Join(c.procPath, "sys", "kernel", "random")
+	var errs sourceErrors
+
+	available, err := readUintFromFile(filepath.Join(randomDir, "entropy_avail"))
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["available"], prometheus.GaugeValue, float64(available))
+	} else {
+		c.logger.Debug("Failed to get available entropy", zap.Error(err))
+		err = fmt.Errorf("failed to get available entropy: %w", err)
+	}
+	errs.add(err)
+
+	poolSize, err := readUintFromFile(filepath.Join(randomDir, "poolsize"))
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["pool_size"], prometheus.GaugeValue, float64(poolSize))
+	} else {
+		c.logger.Debug("Failed to get entropy pool size", zap.Error(err))
+		err = fmt.Errorf("failed to get entropy pool size: %w", err)
+	}
+	errs.add(err)
+
+	return errs.err()
+}
@@ -0,0 +1,35 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestEntropyCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "sys/kernel/random/entropy_avail", "256\n")
+	writeFixture(t, root, "sys/kernel/random/poolsize", "256\n")
+
+	families := gatherFromCollector(t, newEntropyCollector(root, zaptest.NewLogger(t)))
+
+	assert.Equal(t, float64(256), metricValue(t, families["node_entropy_available_bits"], nil))
+	assert.Equal(t, float64(256), metricValue(t, families["node_entropy_pool_size_bits"], nil))
+}
+
+func TestEntropyCollectorFailure(t *testing.T) {
+	root := t.TempDir()
+	c := newEntropyCollector(root, zaptest.NewLogger(t))
+
+	_, err := gatherWithError(t, c)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get available entropy")
+
+	// Older kernels without entropy_avail still report the pool size
+	writeFixture(t, root, "sys/kernel/random/poolsize", "256\n")
+	families, err := gatherWithError(t, c)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(256), metricValue(t, families["node_entropy_pool_size_bits"], nil))
+}
@@ -0,0 +1,110 @@
+//go:build linux
+
+package collector
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// fixturePaths points the collectors at the fake host under testdata/fixtures
+var fixturePaths = Paths{
+	ProcPath: filepath.Join("testdata", "fixtures", "proc"),
+	SysPath:  filepath.Join("testdata", "fixtures", "sys"),
+	RootPath: filepath.Join("testdata", "fixtures", "rootfs"),
+}
+
+// goldenCollectors are enabled one at a time. uname and time report the
+// machine running the tests, and filesystem sizes come from a real statfs, so
+// they are covered by their own tests instead
+var goldenCollectors = []string{
+	"processes", "process_top", "cpu", "cpu_freq", "memory", "vmstat", "loadavg",
+	"diskstats", "netdev", "netstat", "sockstat", "tcpstat", "uptime", "entropy",
+	"interrupts", "softirqs", "thermal", "pressure", "schedstat",
+}
+
+// goldenCollectorConfig enables the named collectors with their default
+// options, except for per-CPU series and a port breakdown that the fixtures
+// cover
+func goldenCollectorConfig(names ...string) config.CollectorConfig {
+	cfg := collectorConfig(names...)
+	cfg.Entries["cpu"].Options.(*cpuOptions).PerCPU = true
+	cfg.Entries["tcpstat"].Options.(*tcpStatOptions).Ports = []int{22}
+	return cfg
+}
+
+func TestCollectorsGolden(t *testing.T) {
+	for _, name := range goldenCollectors {
+		t.Run(name, func(t *testing.T) {
+			cfg := goldenCollectorConfig(name)
+
+			sc, err := NewSystemCollector(cfg, fixturePaths, zaptest.NewLogger(t))
+			require.NoError(t, err)
+			enabled := sc.GetEnabledCollectors()
+			require.Len(t, enabled, 1)
+			require.Contains(t, enabled, name)
+
+			families, err := sc.Collect(context.Background())
+			require.NoError(t, err)
+
+			var got bytes.Buffer
+			for _, family := range families {
+				_, err := expfmt.MetricFamilyToText(&got, family)
+				require.NoError(t, err)
+			}
+
+			goldenPath := filepath.Join("testdata", "golden", name+".prom")
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0755))
+				require.NoError(t, os.WriteFile(goldenPath, got.Bytes(), 0644))
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			require.NoError(t, err, "run go test with -update to create the golden file")
+			assert.Equal(t, string(want), got.String())
+		})
+	}
+}
+
+func TestFilesystemCollectorRootPath(t *testing.T) {
+	cfg := goldenCollectorConfig("filesystem")
+
+	sc, err := NewSystemCollector(cfg, fixturePaths, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	families, err := sc.Collect(context.Background())
+	require.NoError(t, err)
+
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	// Host mountpoints are statted under the fixture root, and only the
+	// device-backed mounts of the fixture mountinfo are reported
+	deviceErrors := byName["node_filesystem_device_error"]
+	require.NotNil(t, deviceErrors)
+	assert.Len(t, deviceErrors.Metric, 3)
+	assert.Equal(t, 0.0, metricValue(t, deviceErrors, map[string]string{"mountpoint": "/", "device": "/dev/vda1"}))
+	assert.Equal(t, 0.0, metricValue(t, deviceErrors, map[string]string{"mountpoint": "/data", "dm_name": "vg--data-root", "lv": "root"}))
+	assert.Equal(t, 1.0, metricValue(t, byName["node_filesystem_readonly"], map[string]string{"mountpoint": "/data"}))
+	assert.Equal(t, 0.0, metricValue(t, byName["node_filesystem_readonly"], map[string]string{"mountpoint": "/"}))
+
+	// /srv is mounted read-write, but the kernel has remounted its superblock
+	// read-only after errors
+	assert.Equal(t, 1.0, metricValue(t, byName["node_filesystem_readonly"], map[string]string{"mountpoint": "/srv"}))
+}
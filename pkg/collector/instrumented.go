@@ -0,0 +1,309 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+)
+
+// CollectorStatus describes an enabled collector and the outcome of its most
+// recent collection. Success is false until the collector has run once, and
+// LastError keeps the most recent failure even after later runs succeed
+type CollectorStatus struct {
+	Enabled   bool
+	Success   bool
+	TimedOut  bool
+	Duration  time.Duration
+	LastError string
+}
+
+// metricsCollector is implemented by every collector of this package. collect
+// sends the metrics and returns an error when the collector could not read
+// the data they come from, which marks the collection as failed. Failures of
+// single items, such as one mount or interface, are only logged
+type metricsCollector interface {
+	Describe(ch chan<- *prometheus.Desc)
+	collect(ch chan<- prometheus.Metric) error
+}
+
+// sourceErrors records the outcome of each source a collector reads, such as
+// a file or a device, so that the collection fails only when none of them
+// could be read
+type sourceErrors struct {
+	sources int
+	errs    []error
+}
+
+// add records the outcome of reading one source
+func (e *sourceErrors) add(err error) {
+	e.sources++
+	if err != nil {
+		e.errs = append(e.errs, err)
+	}
+}
+
+// err returns the errors of all sources when every one of them failed
+func (e *sourceErrors) err() error {
+	if e.sources == 0 || len(e.errs) < e.sources {
+		return nil
+	}
+	return errors.Join(e.errs...)
+}
+
+// collectorAdapter registers a metricsCollector on a prometheus registry,
+// keeping the error of its last collection
+type collectorAdapter struct {
+	metricsCollector
+
+	mu  sync.Mutex
+	err error
+}
+
+func (a *collectorAdapter) Collect(ch chan<- prometheus.Metric) {
+	err := a.collect(ch)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.err = err
+}
+
+// lastError returns the error of the last collection
+func (a *collectorAdapter) lastError() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
+
+// instrumentedCollector runs one collector on its own registry so it can be
+// gathered independently of the others, under a timeout and at its own
+// interval, while timing each collection and recording whether it failed
+type instrumentedCollector struct {
+	registry  *prometheus.Registry
+	collector *collectorAdapter
+	logger    *zap.Logger
+	timeout   time.Duration
+	interval  time.Duration
+
+	mu        sync.Mutex
+	running   bool
+	started   time.Time
+	lastRun   time.Time
+	cached    []*dto.MetricFamily
+	collected bool
+	success   bool
+	timedOut  bool
+	duration  time.Duration
+	lastError string
+}
+
+// newInstrumentedCollector creates the wrapper for the named collector, which
+// is then created with its logger and added with register. A zero interval
+// collects on every cycle
+func newInstrumentedCollector(name string, timeout, interval time.Duration, logger *zap.Logger) *instrumentedCollector {
+	return &instrumentedCollector{
+		registry: prometheus.NewRegistry(),
+		logger:   logger.With(zap.String("collector", name)),
+		timeout:  timeout,
+		interval: interval,
+	}
+}
+
+// register adds the wrapped collector to the registry
+func (c *instrumentedCollector) register(collector metricsCollector) error {
+	c.collector = &collectorAdapter{metricsCollector: collector}
+	return c.registry.Register(c.collector)
+}
+
+// collect gathers the collector's metrics within its timeout of ctx. A
+// collection that misses the deadline keeps running in the background, but its
+// result is dropped and further collections are skipped until it returns, so a
+// read stuck on /proc or sysfs does not pile up goroutines
+func (c *instrumentedCollector) collect(ctx context.Context) []*dto.MetricFamily {
+	c.mu.Lock()
+	// Until it is due again, a collector with its own interval serves the
+	// results of its last collection; a tenth of the interval is allowed for
+	// jitter of the collection cycle
+	if c.interval > 0 && !c.lastRun.IsZero() && time.Since(c.lastRun) < c.interval-c.interval/10 {
+		cached := c.cached
+		c.mu.Unlock()
+		return cached
+	}
+	if c.running {
+		c.recordLocked(time.Since(c.started), true, "skipped: previous collection has not returned")
+		c.mu.Unlock()
+		c.logger.Warn("Skipping collector whose previous collection has not returned",
+			zap.Duration("running_for", time.Since(c.started)))
+		return nil
+	}
+	c.running = true
+	c.started = time.Now()
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	type result struct {
+		families  []*dto.MetricFamily
+		lastError string
+	}
+	done := make(chan result, 1)
+	start := time.Now()
+
+	go func() {
+		families, err := c.registry.Gather()
+		lastError := ""
+		if err != nil {
+			lastError = "failed to gather metrics: " + err.Error()
+		} else if err := c.collector.lastError(); err != nil {
+			c.logger.Debug("Collection failed", zap.Error(err))
+			lastError = err.Error()
+		}
+
+		c.mu.Lock()
+		c.running = false
+		c.mu.Unlock()
+
+		done <- result{families: families, lastError: lastError}
+	}()
+
+	select {
+	case r := <-done:
+		c.record(time.Since(start), false, r.lastError)
+		if c.interval > 0 {
+			c.cache(start, r.families)
+		}
+		return r.families
+	case <-ctx.Done():
+		c.record(time.Since(start), true, "collection did not finish: "+ctx.Err().Error())
+		c.logger.Warn("Dropping results of collector that missed its deadline",
+			zap.Duration("timeout", c.timeout),
+			zap.String("reason", ctx.Err().Error()))
+		return nil
+	}
+}
+
+// record stores the outcome of a collection
+func (c *instrumentedCollector) record(duration time.Duration, timedOut bool, lastError string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordLocked(duration, timedOut, lastError)
+}
+
+func (c *instrumentedCollector) recordLocked(duration time.Duration, timedOut bool, lastError string) {
+	c.collected = true
+	c.success = lastError == ""
+	c.timedOut = timedOut
+	c.duration = duration
+	if lastError != "" {
+		c.lastError = lastError
+	}
+}
+
+// cache keeps the results of a collection until the collector is due again.
+// Its metrics are stamped with the time of the collection, so that when they
+// are sent again on later cycles they keep their original timestamp
+func (c *instrumentedCollector) cache(collectedAt time.Time, families []*dto.MetricFamily) {
+	timestampMs := collectedAt.UnixMilli()
+	for _, family := range families {
+		for _, metric := range family.Metric {
+			if metric.TimestampMs == nil {
+				metric.TimestampMs = &timestampMs
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastRun = collectedAt
+	c.cached = families
+}
+
+// status returns the outcome of the most recent collection
+func (c *instrumentedCollector) status() CollectorStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CollectorStatus{
+		Enabled:   true,
+		Success:   c.collected && c.success,
+		TimedOut:  c.timedOut,
+		Duration:  c.duration,
+		LastError: c.lastError,
+	}
+}
+
+// selfMetricsCollector reports the duration and outcome of the last collection
+// of every collector, so the backend can alert on agents that stopped
+// producing data
+type selfMetricsCollector struct {
+	collectors map[string]*instrumentedCollector
+	descs      map[string]*prometheus.Desc
+}
+
+func (c *selfMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = map[string]*prometheus.Desc{
+		"duration": prometheus.NewDesc("sc_agent_collector_duration_seconds",
+			"Time the collector took to collect its metrics.", []string{"collector"}, nil),
+		"success": prometheus.NewDesc("sc_agent_collector_success",
+			"Whether the collector collected its metrics without errors (1) or not (0).", []string{"collector"}, nil),
+		"timed_out": prometheus.NewDesc("sc_agent_collector_timed_out",
+			"Whether the collector missed its deadline and its metrics were dropped (1) or not (0).", []string{"collector"}, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *selfMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for name, collector := range c.collectors {
+		status := collector.status()
+
+		success, timedOut := 0.0, 0.0
+		if status.Success {
+			success = 1
+		}
+		if status.TimedOut {
+			timedOut = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.descs["duration"], prometheus.GaugeValue, status.Duration.Seconds(), name)
+		ch <- prometheus.MustNewConstMetric(c.descs["success"], prometheus.GaugeValue, success, name)
+		ch <- prometheus.MustNewConstMetric(c.descs["timed_out"], prometheus.GaugeValue, timedOut, name)
+	}
+}
+
+// mergeMetricFamilies combines the families gathered from several registries
+// into one list sorted by name, as a single Gather would return them. The
+// families are copied, as cached results are merged again on later cycles
+func mergeMetricFamilies(groups [][]*dto.MetricFamily) []*dto.MetricFamily {
+	byName := make(map[string]*dto.MetricFamily)
+	var merged []*dto.MetricFamily
+
+	for _, families := range groups {
+		for _, family := range families {
+			existing, ok := byName[family.GetName()]
+			if !ok {
+				existing = &dto.MetricFamily{
+					Name: family.Name,
+					Help: family.Help,
+					Type: family.Type,
+				}
+				byName[family.GetName()] = existing
+				merged = append(merged, existing)
+			}
+			existing.Metric = append(existing.Metric, family.Metric...)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].GetName() < merged[j].GetName()
+	})
+	return merged
+}
@@ -0,0 +1,220 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// flakyCollector emits a single gauge, or fails with err and emits nothing, as
+// collectors do when their data source cannot be read. itemErr is logged as
+// the failure of a single item, which does not fail the collection. When block
+// is set, collect waits for it to be closed first
+type flakyCollector struct {
+	err     error
+	itemErr error
+	block   chan struct{}
+	logger  *zap.Logger
+	desc    *prometheus.Desc
+}
+
+func newFlakyCollector(logger *zap.Logger) *flakyCollector {
+	return &flakyCollector{logger: logger, desc: prometheus.NewDesc("node_flaky", "Flaky test gauge.", nil, nil)}
+}
+
+func (c *flakyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *flakyCollector) collect(ch chan<- prometheus.Metric) error {
+	if c.block != nil {
+		<-c.block
+	}
+	if c.err != nil {
+		return fmt.Errorf("failed to read flaky source: %w", c.err)
+	}
+	if c.itemErr != nil {
+		c.logger.Debug("Failed to read flaky item", zap.Error(c.itemErr))
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1)
+	return nil
+}
+
+func TestInstrumentedCollector(t *testing.T) {
+	ic := newInstrumentedCollector("flaky", time.Second, 0, zaptest.NewLogger(t))
+	flaky := newFlakyCollector(ic.logger)
+	require.NoError(t, ic.register(flaky))
+
+	assert.Equal(t, CollectorStatus{Enabled: true}, ic.status(), "not collected yet")
+
+	families := ic.collect(context.Background())
+	require.Len(t, families, 1)
+	assert.Equal(t, "node_flaky", families[0].GetName())
+	status := ic.status()
+	assert.True(t, status.Success)
+	assert.Empty(t, status.LastError)
+
+	// Failures of single items do not fail the collection
+	flaky.itemErr = errors.New("no such device")
+	assert.Len(t, ic.collect(context.Background()), 1)
+	status = ic.status()
+	assert.True(t, status.Success)
+	assert.Empty(t, status.LastError)
+
+	flaky.err = errors.New("permission denied")
+	assert.Empty(t, ic.collect(context.Background()))
+	status = ic.status()
+	assert.False(t, status.Success)
+	assert.False(t, status.TimedOut)
+	assert.Equal(t, "failed to read flaky source: permission denied", status.LastError)
+
+	// The last error is kept after the collector recovers
+	flaky.err = nil
+	assert.Len(t, ic.collect(context.Background()), 1)
+	status = ic.status()
+	assert.True(t, status.Success)
+	assert.Equal(t, "failed to read flaky source: permission denied", status.LastError)
+}
+
+func TestInstrumentedCollectorTimeout(t *testing.T) {
+	ic := newInstrumentedCollector("flaky", 20*time.Millisecond, 0, zaptest.NewLogger(t))
+	flaky := newFlakyCollector(ic.logger)
+	flaky.block = make(chan struct{})
+	require.NoError(t, ic.register(flaky))
+
+	start := time.Now()
+	assert.Nil(t, ic.collect(context.Background()))
+	assert.Less(t, time.Since(start), time.Second, "collect should return at the deadline")
+
+	status := ic.status()
+	assert.False(t, status.Success)
+	assert.True(t, status.TimedOut)
+	assert.Contains(t, status.LastError, "deadline exceeded")
+
+	// The hung collection is not started a second time
+	assert.Nil(t, ic.collect(context.Background()))
+	assert.True(t, ic.status().TimedOut)
+	assert.Contains(t, ic.status().LastError, "previous collection has not returned")
+
+	// Once it returns, the collector is gathered again
+	close(flaky.block)
+	require.Eventually(t, func() bool {
+		return len(ic.collect(context.Background())) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, ic.status().Success)
+	assert.False(t, ic.status().TimedOut)
+}
+
+func TestInstrumentedCollectorParentDeadline(t *testing.T) {
+	ic := newInstrumentedCollector("flaky", time.Minute, 0, zaptest.NewLogger(t))
+	flaky := newFlakyCollector(ic.logger)
+	flaky.block = make(chan struct{})
+	defer close(flaky.block)
+	require.NoError(t, ic.register(flaky))
+
+	// The earlier deadline of the caller's context wins over the collector timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	assert.Nil(t, ic.collect(ctx))
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, ic.status().TimedOut)
+}
+
+func TestSystemCollectorSelfMetrics(t *testing.T) {
+	// The interrupts file is missing from an empty procfs
+	cfg := collectorConfig("interrupts")
+	cfg.SelfMetrics = true
+	sc, err := NewSystemCollector(cfg, Paths{ProcPath: t.TempDir(), SysPath: t.TempDir(), RootPath: "/"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	byName := collectByName(t, sc)
+	assert.Equal(t, 0.0, metricValue(t, byName["sc_agent_collector_success"], map[string]string{"collector": "interrupts"}))
+	assert.Equal(t, 0.0, metricValue(t, byName["sc_agent_collector_timed_out"], map[string]string{"collector": "interrupts"}))
+	assert.Contains(t, byName, "sc_agent_collector_duration_seconds")
+
+	status := sc.GetEnabledCollectors()["interrupts"]
+	assert.True(t, status.Enabled)
+	assert.False(t, status.Success)
+	assert.Contains(t, status.LastError, "failed to get interrupts")
+}
+
+func TestSystemCollectorInterval(t *testing.T) {
+	procPath := t.TempDir()
+	writeInterrupts := func(count string) {
+		content := "           CPU0\n  0:  " + count + "   IO-APIC   2-edge      timer\n"
+		require.NoError(t, os.WriteFile(filepath.Join(procPath, "interrupts"), []byte(content), 0644))
+	}
+	writeInterrupts("10")
+
+	cfg := collectorConfig("interrupts", "softirqs")
+	cfg.SelfMetrics = true
+	cfg.Entries["interrupts"].Interval = time.Hour
+	sc, err := NewSystemCollector(cfg, Paths{ProcPath: procPath, SysPath: t.TempDir(), RootPath: "/"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	first := collectByName(t, sc)
+	interrupts := first["node_interrupts_total"]
+	require.Len(t, interrupts.Metric, 1)
+	require.NotNil(t, interrupts.Metric[0].TimestampMs, "metrics of collectors with an interval carry their collection time")
+	collectedAt := interrupts.Metric[0].GetTimestampMs()
+
+	// Until the interval elapses the cached result is sent again with its
+	// original timestamp, while the other collectors run on every cycle
+	writeInterrupts("20")
+	second := collectByName(t, sc)
+	interrupts = second["node_interrupts_total"]
+	require.Len(t, interrupts.Metric, 1)
+	assert.Equal(t, 10.0, interrupts.Metric[0].GetCounter().GetValue())
+	assert.Equal(t, collectedAt, interrupts.Metric[0].GetTimestampMs())
+	assert.Len(t, second["sc_agent_collector_success"].Metric, 2)
+	assert.Contains(t, sc.GetEnabledCollectors()["softirqs"].LastError, "failed to get softirqs")
+}
+
+// collectByName runs a collection and returns the metric families keyed by name
+func collectByName(t *testing.T, sc *SystemCollector) map[string]*dto.MetricFamily {
+	t.Helper()
+
+	families, err := sc.Collect(context.Background())
+	require.NoError(t, err)
+
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+	return byName
+}
+
+func TestMergeMetricFamilies(t *testing.T) {
+	family := func(name string, values ...float64) *dto.MetricFamily {
+		f := &dto.MetricFamily{Name: &name}
+		for _, value := range values {
+			f.Metric = append(f.Metric, &dto.Metric{Gauge: &dto.Gauge{Value: &value}})
+		}
+		return f
+	}
+
+	merged := mergeMetricFamilies([][]*dto.MetricFamily{
+		{family("node_b", 1), family("sc_agent_collector_success", 1)},
+		nil,
+		{family("node_a", 2), family("sc_agent_collector_success", 0)},
+	})
+
+	require.Len(t, merged, 3)
+	assert.Equal(t, "node_a", merged[0].GetName())
+	assert.Equal(t, "node_b", merged[1].GetName())
+	assert.Equal(t, "sc_agent_collector_success", merged[2].GetName())
+	assert.Len(t, merged[2].Metric, 2)
+}
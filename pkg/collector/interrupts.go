@@ -0,0 +1,195 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+// irqCounts holds one row of /proc/interrupts or /proc/softirqs
+type irqCounts struct {
+	name    string
+	info    string
+	devices string
+	values  []float64
+}
+
+// irqOptions configures the interrupts and softirqs collectors
+type irqOptions struct {
+	// SumCPUs drops the cpu label and reports the sum over all CPUs
+	SumCPUs bool `yaml:"sum_cpus" json:"sum_cpus"`
+}
+
+func newIRQOptions() config.CollectorOptions {
+	return &irqOptions{}
+}
+
+func (o *irqOptions) Validate() error {
+	return nil
+}
+
+func init() {
+	// Hardware interrupt counts from /proc/interrupts
+	registerCollector("interrupts", true, newIRQOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return &interruptsCollector{procPath: sc.procPath, sumCPUs: options.(*irqOptions).SumCPUs, logger: logger}, nil
+	})
+
+	// Softirq counts from /proc/softirqs
+	registerCollector("softirqs", true, newIRQOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return &softirqsCollector{procPath: sc.procPath, sumCPUs: options.(*irqOptions).SumCPUs, logger: logger}, nil
+	})
+}
+
+type interruptsCollector struct {
+	procPath string
+	sumCPUs  bool
+	logger   *zap.Logger
+	desc     *prometheus.Desc
+}
+
+func (c *interruptsCollector) Describe(ch chan<- *prometheus.Desc) {
+	labels := []string{"cpu", "irq", "type", "devices"}
+	if c.sumCPUs {
+		labels = labels[1:]
+	}
+	c.desc = prometheus.NewDesc("node_interrupts_total", "Interrupt details from /proc/interrupts.", labels, nil)
+
+	ch <- c.desc
+}
+
+func (c *interruptsCollector) collect(ch chan<- prometheus.Metric) error {
+	cpus, rows, err := parseIRQFile(filepath.Join(c.procPath, "interrupts"))
+	if err != nil {
+		return fmt.Errorf("failed to get interrupts: %w", err)
+	}
+
+	for _, row := range rows {
+		emitIRQCounts(ch, c.desc, c.sumCPUs, cpus, row.values, row.name, row.info, row.devices)
+	}
+	return nil
+}
+
+type softirqsCollector struct {
+	procPath string
+	sumCPUs  bool
+	logger   *zap.Logger
+	desc     *prometheus.Desc
+}
+
+func (c *softirqsCollector) Describe(ch chan<- *prometheus.Desc) {
+	labels := []string{"cpu", "type"}
+	if c.sumCPUs {
+		labels = labels[1:]
+	}
+	c.desc = prometheus.NewDesc("node_softirqs_total", "Softirq counts by type from /proc/softirqs.", labels, nil)
+
+	ch <- c.desc
+}
+
+func (c *softirqsCollector) collect(ch chan<- prometheus.Metric) error {
+	cpus, rows, err := parseIRQFile(filepath.Join(c.procPath, "softirqs"))
+	if err != nil {
+		return fmt.Errorf("failed to get softirqs: %w", err)
+	}
+
+	for _, row := range rows {
+		emitIRQCounts(ch, c.desc, c.sumCPUs, cpus, row.values, row.name)
+	}
+	return nil
+}
+
+// emitIRQCounts sends one series per CPU, or a single series with the sum over
+// all CPUs; the cpu label is prepended to the given labels in per-CPU mode.
+// Rows with fewer values than CPUs, such as ERR and MIS, hold a system-wide
+// count and are sent once with an empty cpu label in per-CPU mode
+func emitIRQCounts(ch chan<- prometheus.Metric, desc *prometheus.Desc, sumCPUs bool, cpus []string, values []float64, labels ...string) {
+	if sumCPUs || len(values) < len(cpus) {
+		total := 0.0
+		for _, value := range values {
+			total += value
+		}
+		if !sumCPUs {
+			labels = append([]string{""}, labels...)
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, total, labels...)
+		return
+	}
+
+	for i, value := range values {
+		if i >= len(cpus) {
+			break
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, append([]string{cpus[i]}, labels...)...)
+	}
+}
+
+// parseIRQFile parses /proc/interrupts or /proc/softirqs. The header lists the
+// online CPUs; each row has a name, one count per CPU and, for interrupts, the
+// controller type with the hardware IRQ and the devices sharing the line:
+//
+//	           CPU0       CPU1
+//	 24:     123456          0   PCI-MSI 49152-edge      virtio0-input.0
+//	NMI:          0          0   Non-maskable interrupts
+func parseIRQFile(path string) ([]string, []irqCounts, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return nil, nil, fmt.Errorf("%s is empty", path)
+	}
+
+	var cpus []string
+	for _, header := range strings.Fields(scanner.Text()) {
+		cpus = append(cpus, strings.TrimPrefix(header, "CPU"))
+	}
+
+	var rows []irqCounts
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) < 2 {
+			continue
+		}
+
+		row := irqCounts{name: strings.TrimSuffix(parts[0], ":")}
+
+		// Rows such as ERR and MIS carry a single total instead of per-CPU counts
+		i := 1
+		for ; i < len(parts) && i <= len(cpus); i++ {
+			value, err := strconv.ParseFloat(parts[i], 64)
+			if err != nil {
+				break
+			}
+			row.values = append(row.values, value)
+		}
+
+		if rest := parts[i:]; len(rest) > 0 {
+			if _, err := strconv.Atoi(row.name); err == nil && len(rest) > 1 {
+				// The chip name and hardware IRQ make up the type; the
+				// devices are the trailing comma-separated list
+				devices := len(rest) - 1
+				for devices > 1 && strings.HasSuffix(rest[devices-1], ",") {
+					devices--
+				}
+				row.info = strings.Join(rest[:devices], " ")
+				row.devices = strings.Join(rest[devices:], " ")
+			} else {
+				row.info = strings.Join(rest, " ")
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return cpus, rows, scanner.Err()
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+const interruptsFixture = `           CPU0       CPU1
+  0:         44          0   IO-APIC   2-edge      timer
+ 24:     123456        789   PCI-MSI 49152-edge      virtio0-input.0
+ 25:         10         20   PCI-MSI 49153-edge      virtio0-output.0, virtio0-config
+NMI:          3          4   Non-maskable interrupts
+ERR:          7
+`
+
+const softirqsFixture = `                    CPU0       CPU1
+          HI:          1          0
+       TIMER:     500000     400000
+      NET_TX:        100         50
+      NET_RX:     900000         10
+`
+
+func TestInterruptsCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "interrupts", interruptsFixture)
+	logger := zaptest.NewLogger(t)
+
+	t.Run("per cpu", func(t *testing.T) {
+		families := gatherFromCollector(t, &interruptsCollector{procPath: root, logger: logger})
+
+		interrupts := families["node_interrupts_total"]
+		require.NotNil(t, interrupts)
+		assert.Len(t, interrupts.Metric, 9)
+		assert.Equal(t, float64(789), metricValue(t, interrupts, map[string]string{
+			"cpu": "1", "irq": "24", "type": "PCI-MSI 49152-edge", "devices": "virtio0-input.0",
+		}))
+		assert.Equal(t, float64(44), metricValue(t, interrupts, map[string]string{
+			"cpu": "0", "irq": "0", "type": "IO-APIC 2-edge", "devices": "timer",
+		}))
+		assert.Equal(t, float64(4), metricValue(t, interrupts, map[string]string{
+			"cpu": "1", "irq": "NMI", "type": "Non-maskable interrupts", "devices": "",
+		}))
+
+		// The system-wide error count is not attributed to CPU 0
+		assert.Equal(t, float64(7), metricValue(t, interrupts, map[string]string{"cpu": "", "irq": "ERR"}))
+		for _, metric := range interrupts.Metric {
+			if labelValue(metric, "irq") == "ERR" {
+				assert.Empty(t, labelValue(metric, "cpu"))
+			}
+		}
+	})
+
+	t.Run("summed", func(t *testing.T) {
+		families := gatherFromCollector(t, &interruptsCollector{procPath: root, sumCPUs: true, logger: logger})
+
+		interrupts := families["node_interrupts_total"]
+		require.NotNil(t, interrupts)
+		assert.Len(t, interrupts.Metric, 5)
+		assert.Equal(t, float64(124245), metricValue(t, interrupts, map[string]string{"irq": "24"}))
+		assert.Equal(t, float64(7), metricValue(t, interrupts, map[string]string{"irq": "ERR"}))
+		assert.Equal(t, float64(30), metricValue(t, interrupts, map[string]string{
+			"irq": "25", "type": "PCI-MSI 49153-edge", "devices": "virtio0-output.0, virtio0-config",
+		}))
+		for _, metric := range interrupts.Metric {
+			assert.Empty(t, labelValue(metric, "cpu"))
+		}
+	})
+}
+
+func TestSoftirqsCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "softirqs", softirqsFixture)
+	logger := zaptest.NewLogger(t)
+
+	families := gatherFromCollector(t, &softirqsCollector{procPath: root, logger: logger})
+	assert.Len(t, families["node_softirqs_total"].Metric, 8)
+	assert.Equal(t, float64(900000), metricValue(t, families["node_softirqs_total"], map[string]string{"cpu": "0", "type": "NET_RX"}))
+
+	families = gatherFromCollector(t, &softirqsCollector{procPath: root, sumCPUs: true, logger: logger})
+	assert.Len(t, families["node_softirqs_total"].Metric, 4)
+	assert.Equal(t, float64(900000), metricValue(t, families["node_softirqs_total"], map[string]string{"type": "TIMER"}))
+}
@@ -63,16 +63,9 @@ func TestCollectorGeneratesOnlySupportedMetrics(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	
 	// Test all collectors enabled
-	cfg := config.CollectorConfig{
-		CPU:        true,
-		LoadAvg:    true,
-		Memory:     true,
-		DiskStats:  true,
-		Filesystem: true,
-		NetDev:     true,
-	}
+	cfg := supportedMetricsConfig("cpu", "loadavg", "memory", "diskstats", "filesystem", "netdev")
 
-	collector, err := NewSystemCollector(cfg, logger)
+	collector, err := NewSystemCollector(cfg, DefaultPaths, logger)
 	if err != nil {
 		t.Skip(skipMessageNonLinuxValidation + " (cannot access /proc)")
 	}
@@ -119,6 +112,19 @@ func TestCollectorGeneratesOnlySupportedMetrics(t *testing.T) {
 		len(supportedMetricsFound), supportedMetricsFound)
 }
 
+// supportedMetricsConfig enables the named collectors without the options
+// that add metrics outside the whitelist
+func supportedMetricsConfig(names ...string) config.CollectorConfig {
+	cfg := collectorConfig(names...)
+	cfg.Entries["cpu"].Options.(*cpuOptions).Extended = false
+	cfg.Entries["diskstats"].Options.(*diskStatsOptions).Extended = false
+	cfg.Entries["filesystem"].Options.(*filesystemOptions).Extended = false
+	netdev := cfg.Entries["netdev"].Options.(*networkOptions)
+	netdev.Extended = false
+	netdev.AddressInfo = false
+	return cfg
+}
+
 // collectorTestCase represents a test case for individual collector testing
 type collectorTestCase struct {
 	name             string
@@ -131,12 +137,12 @@ func getCollectorTestCases() []collectorTestCase {
 	return []collectorTestCase{
 		{
 			name: "CPU collector",
-			config: config.CollectorConfig{CPU: true},
+			config: supportedMetricsConfig("cpu"),
 			expectedMetrics: []string{"node_cpu_seconds_total"},
 		},
 		{
 			name: "Memory collector", 
-			config: config.CollectorConfig{Memory: true},
+			config: supportedMetricsConfig("memory"),
 			expectedMetrics: []string{
 				"node_memory_MemTotal_bytes",
 				"node_memory_MemFree_bytes", 
@@ -149,7 +155,7 @@ func getCollectorTestCases() []collectorTestCase {
 		},
 		{
 			name: "Load average collector",
-			config: config.CollectorConfig{LoadAvg: true}, 
+			config: supportedMetricsConfig("loadavg"), 
 			expectedMetrics: []string{
 				"node_load1",
 				"node_load5", 
@@ -158,7 +164,7 @@ func getCollectorTestCases() []collectorTestCase {
 		},
 		{
 			name: "Disk stats collector",
-			config: config.CollectorConfig{DiskStats: true},
+			config: supportedMetricsConfig("diskstats"),
 			expectedMetrics: []string{
 				"node_disk_reads_completed_total",
 				"node_disk_writes_completed_total",
@@ -168,7 +174,7 @@ func getCollectorTestCases() []collectorTestCase {
 		},
 		{
 			name: "Network collector",
-			config: config.CollectorConfig{NetDev: true},
+			config: supportedMetricsConfig("netdev"),
 			expectedMetrics: []string{
 				"node_network_receive_bytes_total",
 				"node_network_transmit_bytes_total",
@@ -178,7 +184,7 @@ func getCollectorTestCases() []collectorTestCase {
 		},
 		{
 			name: "Filesystem collector",
-			config: config.CollectorConfig{Filesystem: true},
+			config: supportedMetricsConfig("filesystem"),
 			expectedMetrics: []string{
 				"node_filesystem_size_bytes",
 			},
@@ -218,7 +224,7 @@ func validateCollectorMetrics(t *testing.T, metricFamilies []*dto.MetricFamily,
 
 // testSingleCollectorCompliance tests a single collector for metric compliance
 func testSingleCollectorCompliance(t *testing.T, tc collectorTestCase, logger *zap.Logger) {
-	collector, err := NewSystemCollector(tc.config, logger)
+	collector, err := NewSystemCollector(tc.config, DefaultPaths, logger)
 	if err != nil {
 		t.Skip(skipMessageNonLinuxValidation)
 	}
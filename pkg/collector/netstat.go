@@ -0,0 +1,188 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+// defaultNetStatFields selects TCP retransmits, opens, resets and listen queue
+// overflows, UDP buffer errors and ICMP errors from /proc/net/{snmp,snmp6,netstat}
+const defaultNetStatFields = `^(Tcp_(ActiveOpens|PassiveOpens|RetransSegs|EstabResets|OutRsts|AttemptFails|CurrEstab)|TcpExt_(ListenOverflows|ListenDrops|TCPSynRetrans|TCPTimeouts)|Udp6?_(RcvbufErrors|SndbufErrors|InErrors)|Icmp6?_(InErrors|OutErrors))$`
+
+// netstatOptions configures the netstat collector
+type netstatOptions struct {
+	// Fields selects the <Protocol>_<Field> counters to report
+	Fields string `yaml:"fields" json:"fields"`
+}
+
+func newNetStatOptions() config.CollectorOptions {
+	return &netstatOptions{Fields: defaultNetStatFields}
+}
+
+func (o *netstatOptions) Validate() error {
+	return validatePattern("fields", o.Fields)
+}
+
+func init() {
+	// TCP, UDP, IP and ICMP protocol counters from /proc/net
+	registerCollector("netstat", true, newNetStatOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newNetStatCollector(sc.procPath, options.(*netstatOptions).Fields, logger)
+	})
+}
+
+type netstatCollector struct {
+	procPath    string
+	fieldFilter *regexp.Regexp
+	logger      *zap.Logger
+}
+
+// newNetStatCollector creates a protocol counter collector that only reports
+// <Protocol>_<Field> names matching the given pattern, falling back to the
+// default field set when empty
+func newNetStatCollector(procPath, fields string, logger *zap.Logger) (*netstatCollector, error) {
+	if fields == "" {
+		fields = defaultNetStatFields
+	}
+
+	fieldFilter, err := regexp.Compile(fields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid netstat field pattern %q: %w", fields, err)
+	}
+
+	return &netstatCollector{procPath: procPath, fieldFilter: fieldFilter, logger: logger}, nil
+}
+
+// Describe sends no descriptors: the metric set depends on the counters the
+// running kernel exposes, so the collector is registered as unchecked
+func (c *netstatCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func (c *netstatCollector) collect(ch chan<- prometheus.Metric) error {
+	stats := make(map[string]map[string]string)
+	var errs sourceErrors
+
+	for _, file := range []string{"net/netstat", "net/snmp"} {
+		protocols, err := parseNetStatsFile(filepath.Join(c.procPath, file))
+		if err != nil {
+			c.logger.Debug("Failed to read protocol counters", zap.String("file", file), zap.Error(err))
+			errs.add(fmt.Errorf("failed to read %s: %w", file, err))
+			continue
+		}
+		errs.add(nil)
+		for protocol, fields := range protocols {
+			stats[protocol] = fields
+		}
+	}
+
+	// snmp6 is absent when IPv6 is disabled
+	if protocols, err := parseSNMP6File(filepath.Join(c.procPath, "net/snmp6")); err == nil {
+		errs.add(nil)
+		for protocol, fields := range protocols {
+			stats[protocol] = fields
+		}
+	} else if !os.IsNotExist(err) {
+		c.logger.Debug("Failed to read IPv6 protocol counters", zap.Error(err))
+		errs.add(fmt.Errorf("failed to read net/snmp6: %w", err))
+	}
+
+	for protocol, fields := range stats {
+		for field, rawValue := range fields {
+			key := protocol + "_" + field
+			if !c.fieldFilter.MatchString(key) {
+				continue
+			}
+
+			value, err := strconv.ParseFloat(rawValue, 64)
+			if err != nil {
+				c.logger.Debug("Failed to parse protocol counter", zap.String("field", key), zap.Error(err))
+				continue
+			}
+
+			desc := prometheus.NewDesc(
+				"node_netstat_"+key,
+				fmt.Sprintf("Statistic %s%s.", protocol, field),
+				nil, nil,
+			)
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.UntypedValue, value)
+		}
+	}
+
+	return errs.err()
+}
+
+// parseNetStatsFile parses /proc/net/snmp and /proc/net/netstat, where each
+// protocol has a header line of field names followed by a line of values:
+//
+//	Tcp: RtoAlgorithm RtoMin ...
+//	Tcp: 1 200 ...
+func parseNetStatsFile(path string) (map[string]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	protocols := make(map[string]map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		names := strings.Fields(scanner.Text())
+		if !scanner.Scan() {
+			break
+		}
+		values := strings.Fields(scanner.Text())
+
+		if len(names) == 0 || len(names) != len(values) || names[0] != values[0] {
+			return nil, fmt.Errorf("mismatched header and value lines in %s", path)
+		}
+
+		protocol := strings.TrimSuffix(names[0], ":")
+		fields := make(map[string]string, len(names)-1)
+		for i := 1; i < len(names); i++ {
+			fields[names[i]] = values[i]
+		}
+		protocols[protocol] = fields
+	}
+
+	return protocols, scanner.Err()
+}
+
+// parseSNMP6File parses /proc/net/snmp6, which lists one "Ip6InReceives 123"
+// pair per line with the protocol name ending at the "6"
+func parseSNMP6File(path string) (map[string]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	protocols := make(map[string]map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) != 2 {
+			continue
+		}
+
+		idx := strings.Index(parts[0], "6")
+		if idx < 0 || idx == len(parts[0])-1 {
+			continue
+		}
+
+		protocol, field := parts[0][:idx+1], parts[0][idx+1:]
+		if protocols[protocol] == nil {
+			protocols[protocol] = make(map[string]string)
+		}
+		protocols[protocol][field] = parts[1]
+	}
+
+	return protocols, scanner.Err()
+}
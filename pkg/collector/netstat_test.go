@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func writeNetStatFixtures(t *testing.T, root string) {
+	t.Helper()
+
+	writeFixture(t, root, "net/snmp", `Ip: Forwarding DefaultTTL InReceives InHdrErrors
+Ip: 1 64 2000000 0
+Icmp: InMsgs InErrors OutMsgs OutErrors
+Icmp: 45 3 50 1
+Tcp: RtoAlgorithm RtoMin RtoMax MaxConn ActiveOpens PassiveOpens AttemptFails EstabResets CurrEstab InSegs OutSegs RetransSegs InErrs OutRsts
+Tcp: 1 200 120000 -1 3000 1500 20 40 12 900000 800000 321 0 77
+Udp: InDatagrams NoPorts InErrors OutDatagrams RcvbufErrors SndbufErrors
+Udp: 5000 10 2 4000 8 1
+`)
+	writeFixture(t, root, "net/netstat", `TcpExt: SyncookiesSent ListenOverflows ListenDrops TCPTimeouts
+TcpExt: 0 13 14 99
+IpExt: InOctets OutOctets
+IpExt: 123456 654321
+`)
+	writeFixture(t, root, "net/snmp6", `Ip6InReceives                   	1000
+Icmp6InErrors                   	4
+Udp6RcvbufErrors                	6
+Udp6InDatagrams                 	300
+`)
+}
+
+func TestNetStatCollector(t *testing.T) {
+	root := t.TempDir()
+	writeNetStatFixtures(t, root)
+
+	c, err := newNetStatCollector(root, "", zaptest.NewLogger(t))
+	require.NoError(t, err)
+	families := gatherFromCollector(t, c)
+
+	assert.Equal(t, float64(321), metricValue(t, families["node_netstat_Tcp_RetransSegs"], nil))
+	assert.Equal(t, float64(3000), metricValue(t, families["node_netstat_Tcp_ActiveOpens"], nil))
+	assert.Equal(t, float64(1500), metricValue(t, families["node_netstat_Tcp_PassiveOpens"], nil))
+	assert.Equal(t, float64(77), metricValue(t, families["node_netstat_Tcp_OutRsts"], nil))
+	assert.Equal(t, float64(13), metricValue(t, families["node_netstat_TcpExt_ListenOverflows"], nil))
+	assert.Equal(t, float64(14), metricValue(t, families["node_netstat_TcpExt_ListenDrops"], nil))
+	assert.Equal(t, float64(8), metricValue(t, families["node_netstat_Udp_RcvbufErrors"], nil))
+	assert.Equal(t, float64(1), metricValue(t, families["node_netstat_Udp_SndbufErrors"], nil))
+	assert.Equal(t, float64(3), metricValue(t, families["node_netstat_Icmp_InErrors"], nil))
+	assert.Equal(t, float64(4), metricValue(t, families["node_netstat_Icmp6_InErrors"], nil))
+	assert.Equal(t, float64(6), metricValue(t, families["node_netstat_Udp6_RcvbufErrors"], nil))
+	assert.NotContains(t, families, "node_netstat_IpExt_InOctets")
+	assert.NotContains(t, families, "node_netstat_Tcp_MaxConn")
+}
+
+func TestNetStatCollectorCustomFields(t *testing.T) {
+	root := t.TempDir()
+	writeNetStatFixtures(t, root)
+
+	c, err := newNetStatCollector(root, "^(IpExt_.*|Tcp_MaxConn)$", zaptest.NewLogger(t))
+	require.NoError(t, err)
+	families := gatherFromCollector(t, c)
+
+	assert.Len(t, families, 3)
+	assert.Equal(t, float64(654321), metricValue(t, families["node_netstat_IpExt_OutOctets"], nil))
+	assert.Equal(t, float64(-1), metricValue(t, families["node_netstat_Tcp_MaxConn"], nil))
+}
+
+func TestNetStatCollectorWithoutIPv6(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "net/snmp", "Tcp: ActiveOpens RetransSegs\nTcp: 5 6\n")
+
+	c, err := newNetStatCollector(root, "", zaptest.NewLogger(t))
+	require.NoError(t, err)
+	families, err := gatherWithError(t, c)
+	require.NoError(t, err, "missing files are fine as long as one is read")
+
+	assert.Len(t, families, 2)
+	assert.Equal(t, float64(6), metricValue(t, families["node_netstat_Tcp_RetransSegs"], nil))
+}
+
+func TestNetStatCollectorFailure(t *testing.T) {
+	c, err := newNetStatCollector(t.TempDir(), "", zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	families, err := gatherWithError(t, c)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read net/snmp")
+	assert.Empty(t, families)
+}
@@ -1,10 +1,18 @@
 package collector
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -27,31 +35,138 @@ var ignoredFSTypes = map[string]bool{
 	"nfs": true, "nfs4": true, "cifs": true, "smb": true,
 }
 
-// getInterfaceIPAddress retrieves the first non-loopback IPv4 address of an interface
-func getInterfaceIPAddress(interfaceName string) (string, error) {
+// errCollectorUnavailable is returned by collectors whose data source does not
+// exist on this host, so they can be skipped without reporting a failure
+var errCollectorUnavailable = errors.New("collector data source not available")
+
+// interfaceAddrs returns every address assigned to an interface, including
+// IPv6 and secondary addresses
+func interfaceAddrs(interfaceName string) ([]net.Addr, error) {
 	iface, err := net.InterfaceByName(interfaceName)
 	if err != nil {
-		return "", fmt.Errorf("interface %s not found: %w", interfaceName, err)
+		return nil, fmt.Errorf("interface %s not found: %w", interfaceName, err)
 	}
 
 	addrs, err := iface.Addrs()
 	if err != nil {
-		return "", fmt.Errorf("failed to get addresses: %w", err)
+		return nil, fmt.Errorf("failed to get addresses: %w", err)
 	}
 
-	for _, addr := range addrs {
-		ipNet, ok := addr.(*net.IPNet)
-		if !ok {
-			continue
+	return addrs, nil
+}
+
+// addressScope approximates the kernel address scope of an IP address
+func addressScope(ip net.IP) string {
+	switch {
+	case ip.IsLoopback():
+		return "host"
+	case ip.IsLinkLocalUnicast():
+		return "link"
+	default:
+		return "global"
+	}
+}
+
+// readUintFromFile reads a single unsigned integer from a procfs or sysfs file
+func readUintFromFile(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return value, nil
+}
+
+// readIntFromFile reads a single signed integer from a sysfs file, for values such as
+// temperatures that may be negative
+func readIntFromFile(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return value, nil
+}
+
+// readMounts returns the mount table of the host. Init's view is preferred, so
+// that a containerised agent sees the host mounts rather than its own; it falls
+// back to the agent's view when /proc/1 is not readable
+func readMounts(procPath string) ([]*procfs.MountInfo, error) {
+	procFS, err := procfs.NewFS(procPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if proc, err := procFS.Proc(1); err == nil {
+		if mounts, err := proc.MountInfo(); err == nil {
+			return mounts, nil
 		}
+	}
+
+	self, err := procFS.Self()
+	if err != nil {
+		return nil, err
+	}
+	return self.MountInfo()
+}
+
+// validatePattern checks that an option holds a valid regular expression
+func validatePattern(option, pattern string) error {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("invalid %s: %w", option, err)
+	}
+	return nil
+}
+
+// readCPUList reads a kernel CPU list file such as /sys/devices/system/cpu/online,
+// whose contents look like "0-3,5,7-8"
+func readCPUList(path string) ([]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCPUList(strings.TrimSpace(string(data)))
+}
+
+// parseCPUList expands a kernel CPU list such as "0-3,5" into individual CPU numbers
+func parseCPUList(list string) ([]int, error) {
+	var cpus []int
+	if list == "" {
+		return cpus, nil
+	}
 
-		ip := ipNet.IP
-		if ip.To4() != nil && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() {
-			return ip.String(), nil
+	for _, part := range strings.Split(list, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU list %q: %w", list, err)
+		}
+
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid CPU list %q: %w", list, err)
+			}
+		}
+
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
 		}
 	}
 
-	return "", fmt.Errorf("no valid IPv4 address found on interface %s", interfaceName)
+	return cpus, nil
 }
 
 // Collector defines the interface for metric collectors
@@ -59,88 +174,94 @@ type Collector interface {
 	Collect(ctx context.Context) ([]*dto.MetricFamily, error)
 }
 
+// Paths locates the host's procfs, sysfs and root filesystem, which differ from
+// /proc, /sys and / when the agent runs in a container with the host mounted
+// elsewhere
+type Paths struct {
+	ProcPath string
+	SysPath  string
+	RootPath string
+}
+
+// DefaultPaths are the locations used when the agent runs directly on the host
+var DefaultPaths = Paths{ProcPath: "/proc", SysPath: "/sys", RootPath: "/"}
+
 // SystemCollector implements system metrics collection using Prometheus collectors and procfs
 type SystemCollector struct {
-	registry    *prometheus.Registry
 	logger      *zap.Logger
 	enabled     map[string]bool
+	collectors  map[string]*instrumentedCollector
+	timeout     time.Duration
+	selfMetrics *prometheus.Registry
 	procFS      procfs.FS
+	procPath    string
+	sysPath     string
+	rootPath    string
+	devices     *blockDeviceResolver
 	lastCollect time.Time
 }
 
 // NewSystemCollector creates a new system collector using Prometheus libraries
-func NewSystemCollector(cfg config.CollectorConfig, logger *zap.Logger) (*SystemCollector, error) {
-	registry := prometheus.NewRegistry()
+func NewSystemCollector(cfg config.CollectorConfig, paths Paths, logger *zap.Logger) (*SystemCollector, error) {
 	enabled := make(map[string]bool)
 
 	// Initialize procfs
-	procFS, err := procfs.NewDefaultFS()
+	procFS, err := procfs.NewFS(paths.ProcPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize procfs: %w", err)
 	}
 
 	sc := &SystemCollector{
-		registry: registry,
-		logger:   logger,
-		enabled:  enabled,
-		procFS:   procFS,
+		logger:     logger,
+		enabled:    enabled,
+		collectors: make(map[string]*instrumentedCollector),
+		timeout:    cfg.Timeout,
+		procFS:     procFS,
+		procPath:   paths.ProcPath,
+		sysPath:    paths.SysPath,
+		rootPath:   paths.RootPath,
+		devices:    newBlockDeviceResolver(paths.RootPath, paths.SysPath),
+	}
+	if sc.timeout <= 0 {
+		sc.timeout = config.DefaultCollectorTimeout
 	}
 
 	// Go runtime and process metrics removed - not useful for VM monitoring
 	// These only track the agent itself, not the VM performance
 
-	// Add custom system metrics based on configuration
-	if cfg.CPU {
-		if err := sc.addCPUCollector(registry); err == nil {
-			enabled["cpu"] = true
-			logger.Info("Enabled CPU collector")
-		} else {
-			logger.Warn("Failed to enable CPU collector", zap.Error(err))
+	// Add the registered collectors enabled in the configuration
+	var names []string
+	for name, entry := range cfg.Entries {
+		if entry.Enabled {
+			names = append(names, name)
 		}
 	}
+	sort.Strings(names)
 
-	if cfg.Memory {
-		if err := sc.addMemoryCollector(registry); err == nil {
-			enabled["memory"] = true
-			logger.Info("Enabled memory collector")
-		} else {
-			logger.Warn("Failed to enable memory collector", zap.Error(err))
-		}
-	}
-
-	if cfg.LoadAvg {
-		if err := sc.addLoadAvgCollector(registry); err == nil {
-			enabled["loadavg"] = true
-			logger.Info("Enabled load average collector")
-		} else {
-			logger.Warn("Failed to enable load average collector", zap.Error(err))
+	for _, name := range names {
+		entry := cfg.Entries[name]
+		factory, ok := factories[name]
+		if !ok {
+			logger.Warn("Ignoring unknown collector", zap.String("collector", name))
+			continue
 		}
-	}
 
-	if cfg.DiskStats {
-		if err := sc.addDiskStatsCollector(registry); err == nil {
-			enabled["diskstats"] = true
-			logger.Info("Enabled disk stats collector")
-		} else {
-			logger.Warn("Failed to enable disk stats collector", zap.Error(err))
+		ic := newInstrumentedCollector(name, sc.timeout, entry.Interval, logger)
+		c, err := factory(sc, entry.Options, ic.logger)
+		if err == nil {
+			err = ic.register(c)
 		}
-	}
-
-	if cfg.NetDev {
-		if err := sc.addNetworkCollector(registry); err == nil {
-			enabled["network"] = true
-			logger.Info("Enabled network collector")
+		if err == nil {
+			sc.collectors[name] = ic
+			enabled[name] = true
+			logger.Info("Enabled collector",
+				zap.String("collector", name),
+				zap.Any("options", entry.Options),
+				zap.Duration("interval", entry.Interval))
+		} else if errors.Is(err, errCollectorUnavailable) {
+			logger.Info("Skipping collector", zap.String("collector", name), zap.Error(err))
 		} else {
-			logger.Warn("Failed to enable network collector", zap.Error(err))
-		}
-	}
-
-	if cfg.Filesystem {
-		if err := sc.addFilesystemCollector(registry); err == nil {
-			enabled["filesystem"] = true
-			logger.Info("Enabled filesystem collector")
-		} else {
-			logger.Warn("Failed to enable filesystem collector", zap.Error(err))
+			logger.Warn("Failed to enable collector", zap.String("collector", name), zap.Error(err))
 		}
 	}
 
@@ -148,6 +269,11 @@ func NewSystemCollector(cfg config.CollectorConfig, logger *zap.Logger) (*System
 		return nil, fmt.Errorf("no collectors enabled")
 	}
 
+	if cfg.SelfMetrics {
+		sc.selfMetrics = prometheus.NewRegistry()
+		sc.selfMetrics.MustRegister(&selfMetricsCollector{collectors: sc.collectors})
+	}
+
 	logger.Info("SystemCollector initialized", 
 		zap.Int("enabled_collectors", len(enabled)),
 		zap.Any("collectors", enabled))
@@ -155,46 +281,59 @@ func NewSystemCollector(cfg config.CollectorConfig, logger *zap.Logger) (*System
 	return sc, nil
 }
 
-// addCPUCollector adds CPU metrics using procfs
-func (sc *SystemCollector) addCPUCollector(registry *prometheus.Registry) error {
-	cpuCollector := &cpuCollector{procFS: sc.procFS, logger: sc.logger}
-	registry.MustRegister(cpuCollector)
-	return nil
-}
-
-// addMemoryCollector adds memory metrics using procfs
-func (sc *SystemCollector) addMemoryCollector(registry *prometheus.Registry) error {
-	memoryCollector := &memoryCollector{procFS: sc.procFS, logger: sc.logger}
-	registry.MustRegister(memoryCollector)
-	return nil
-}
-
-// addLoadAvgCollector adds load average metrics using procfs
-func (sc *SystemCollector) addLoadAvgCollector(registry *prometheus.Registry) error {
-	loadAvgCollector := &loadAvgCollector{procFS: sc.procFS, logger: sc.logger}
-	registry.MustRegister(loadAvgCollector)
-	return nil
-}
-
-// addDiskStatsCollector adds disk statistics metrics using procfs
-func (sc *SystemCollector) addDiskStatsCollector(registry *prometheus.Registry) error {
-	diskStatsCollector := &diskStatsCollector{procFS: sc.procFS, logger: sc.logger}
-	registry.MustRegister(diskStatsCollector)
-	return nil
-}
+func init() {
+	// CPU time using procfs, and online state using sysfs
+	registerCollector("cpu", true, newCPUOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		opts := options.(*cpuOptions)
+		return &cpuCollector{
+			procFS:   sc.procFS,
+			sysPath:  sc.sysPath,
+			perCPU:   opts.PerCPU,
+			extended: opts.Extended,
+			logger:   logger,
+		}, nil
+	})
+
+	// Memory metrics using procfs
+	registerCollector("memory", true, newMemoryOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		opts := options.(*memoryOptions)
+		return newMemoryCollector(sc.procPath, opts.Include, opts.Exclude, logger)
+	})
+
+	// Load average metrics using procfs
+	registerCollector("loadavg", true, newNoOptions, func(sc *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return &loadAvgCollector{procFS: sc.procFS, logger: logger}, nil
+	})
+
+	// Disk statistics metrics using procfs
+	registerCollector("diskstats", true, newDiskStatsOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		opts := options.(*diskStatsOptions)
+		return newDiskStatsCollector(sc.procPath, sc.sysPath, sc.devices, opts.Extended, opts.DeviceInclude, opts.DeviceExclude, logger)
+	})
+
+	// Network device metrics using procfs and sysfs
+	registerCollector("netdev", true, newNetworkOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		opts := options.(*networkOptions)
+
+		// Addresses are looked up in the agent's own network namespace, which
+		// only holds the interfaces listed in procfs and sysfs when the agent
+		// reads the host paths directly
+		addressInfo := opts.AddressInfo
+		if addressInfo && (filepath.Clean(sc.procPath) != DefaultPaths.ProcPath || filepath.Clean(sc.sysPath) != DefaultPaths.SysPath) {
+			logger.Info("Disabling network address info outside the default host paths",
+				zap.String("proc_path", sc.procPath),
+				zap.String("sys_path", sc.sysPath))
+			addressInfo = false
+		}
 
-// addNetworkCollector adds network metrics using procfs
-func (sc *SystemCollector) addNetworkCollector(registry *prometheus.Registry) error {
-	networkCollector := &networkCollector{procFS: sc.procFS, logger: sc.logger}
-	registry.MustRegister(networkCollector)
-	return nil
-}
+		return newNetworkCollector(sc.procFS, sc.sysPath, opts.Extended, addressInfo, opts.DeviceInclude, opts.DeviceExclude, logger)
+	})
 
-// addFilesystemCollector adds filesystem metrics
-func (sc *SystemCollector) addFilesystemCollector(registry *prometheus.Registry) error {
-	filesystemCollector := &filesystemCollector{procFS: sc.procFS, logger: sc.logger}
-	registry.MustRegister(filesystemCollector)
-	return nil
+	// Filesystem metrics
+	registerCollector("filesystem", true, newFilesystemOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		opts := options.(*filesystemOptions)
+		return newFilesystemCollector(sc.procPath, sc.rootPath, sc.devices, opts.Extended, opts.StatfsTimeout, logger), nil
+	})
 }
 
 // Collect gathers metrics from all enabled collectors
@@ -208,12 +347,36 @@ func (sc *SystemCollector) Collect(ctx context.Context) ([]*dto.MetricFamily, er
 	sc.logger.Debug("Starting metric collection")
 	start := time.Now()
 
-	// Gather metrics from the registry
-	metricFamilies, err := sc.registry.Gather()
-	if err != nil {
-		sc.logger.Error("Failed to gather metrics from registry", zap.Error(err))
-		return nil, fmt.Errorf("failed to gather metrics: %w", err)
+	// Collectors run concurrently, each under its own timeout derived from ctx,
+	// so a stalled read holds up neither the others nor the cycle. Collectors
+	// with their own interval return their cached results until they are due
+	names := make([]string, 0, len(sc.collectors))
+	for name := range sc.collectors {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+
+	groups := make([][]*dto.MetricFamily, len(names), len(names)+1)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			groups[i] = sc.collectors[name].collect(ctx)
+		}()
+	}
+	wg.Wait()
+
+	// Self-metrics are gathered last so they describe this cycle
+	if sc.selfMetrics != nil {
+		families, err := sc.selfMetrics.Gather()
+		if err != nil {
+			sc.logger.Error("Failed to gather collector self-metrics", zap.Error(err))
+		}
+		groups = append(groups, families)
+	}
+
+	metricFamilies := mergeMetricFamilies(groups)
 
 	sc.lastCollect = time.Now()
 	collectDuration := time.Since(start)
@@ -226,11 +389,12 @@ func (sc *SystemCollector) Collect(ctx context.Context) ([]*dto.MetricFamily, er
 	return metricFamilies, nil
 }
 
-// GetEnabledCollectors returns a map of enabled collector names
-func (sc *SystemCollector) GetEnabledCollectors() map[string]bool {
-	result := make(map[string]bool)
-	for k, v := range sc.enabled {
-		result[k] = v
+// GetEnabledCollectors returns the status of each enabled collector by name,
+// including the outcome of its last collection
+func (sc *SystemCollector) GetEnabledCollectors() map[string]CollectorStatus {
+	result := make(map[string]CollectorStatus, len(sc.collectors))
+	for name, ic := range sc.collectors {
+		result[name] = ic.status()
 	}
 	return result
 }
@@ -243,92 +407,240 @@ func (sc *SystemCollector) Close() error {
 
 // Custom collector implementations using procfs
 
+// cpuOptions configures the cpu collector
+type cpuOptions struct {
+	// PerCPU emits node_cpu_seconds_total per core instead of the aggregate row
+	PerCPU bool `yaml:"per_cpu" json:"per_cpu"`
+	// Extended adds guest CPU time, the online CPU count and per-CPU online state
+	Extended bool `yaml:"extended" json:"extended"`
+}
+
+func newCPUOptions() config.CollectorOptions {
+	return &cpuOptions{Extended: true}
+}
+
+func (o *cpuOptions) Validate() error {
+	return nil
+}
+
 type cpuCollector struct {
-	procFS procfs.FS
-	logger *zap.Logger
-	desc   *prometheus.Desc
+	procFS   procfs.FS
+	sysPath  string
+	perCPU   bool
+	extended bool
+	logger   *zap.Logger
+	desc     *prometheus.Desc
+	descs    map[string]*prometheus.Desc
 }
 
 func (c *cpuCollector) Describe(ch chan<- *prometheus.Desc) {
-	c.desc = prometheus.NewDesc("node_cpu_seconds_total", "Seconds the CPUs spent in each mode.", []string{"mode"}, nil)
+	labels := []string{"mode"}
+	if c.perCPU {
+		labels = []string{"cpu", "mode"}
+	}
+
+	c.desc = prometheus.NewDesc("node_cpu_seconds_total", "Seconds the CPUs spent in each mode.", labels, nil)
 	ch <- c.desc
+
+	if !c.extended {
+		return
+	}
+
+	c.descs = map[string]*prometheus.Desc{
+		"guest":  prometheus.NewDesc("node_cpu_guest_seconds_total", "Seconds the CPUs spent in guests (VMs) for each mode.", labels, nil),
+		"count":  prometheus.NewDesc("node_cpu_count", "Number of online CPUs.", nil, nil),
+		"online": prometheus.NewDesc("node_cpu_online", "Whether the CPU is online (1) or offline (0).", []string{"cpu"}, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
 }
 
-func (c *cpuCollector) Collect(ch chan<- prometheus.Metric) {
+func (c *cpuCollector) collect(ch chan<- prometheus.Metric) error {
 	stat, err := c.procFS.Stat()
 	if err != nil {
-		c.logger.Debug("Failed to get CPU stats", zap.Error(err))
-		return
+		return fmt.Errorf("failed to get CPU stats: %w", err)
 	}
 
-	// Only emit aggregate CPU stats (first entry in stat.CPU is the sum of all cores)
-	if len(stat.CPU) == 0 {
-		c.logger.Debug("No CPU stats available")
-		return
+	if !c.perCPU {
+		// stat.CPUTotal is the aggregate "cpu" row summed across all cores
+		c.emitCPUStat(ch, stat.CPUTotal)
+	} else {
+		if len(stat.CPU) == 0 {
+			c.logger.Debug("No per-CPU stats available")
+		}
+		for cpuID, cpu := range stat.CPU {
+			c.emitCPUStat(ch, cpu, strconv.FormatInt(cpuID, 10))
+		}
 	}
 
-	cpu := stat.CPU[0] // First entry is aggregate across all cores
-
-	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.User, "user")
-	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.Nice, "nice")
-	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.System, "system")
-	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.Idle, "idle")
-	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.Iowait, "iowait")
-	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.IRQ, "irq")
-	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.SoftIRQ, "softirq")
-	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.Steal, "steal")
+	if c.extended {
+		c.collectOnlineState(ch)
+	}
+	return nil
 }
 
-type memoryCollector struct {
-	procFS procfs.FS
-	logger *zap.Logger
-	descs  map[string]*prometheus.Desc
+// emitCPUStat sends the mode breakdown of one CPU row, optionally prefixed with a cpu label
+func (c *cpuCollector) emitCPUStat(ch chan<- prometheus.Metric, cpu procfs.CPUStat, cpuLabel ...string) {
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.User, append(cpuLabel, "user")...)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.Nice, append(cpuLabel, "nice")...)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.System, append(cpuLabel, "system")...)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.Idle, append(cpuLabel, "idle")...)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.Iowait, append(cpuLabel, "iowait")...)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.IRQ, append(cpuLabel, "irq")...)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.SoftIRQ, append(cpuLabel, "softirq")...)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, cpu.Steal, append(cpuLabel, "steal")...)
+
+	if !c.extended {
+		return
+	}
+
+	// Guest time is already included in user and nice, so it is reported as a
+	// separate metric to keep sums over node_cpu_seconds_total correct
+	ch <- prometheus.MustNewConstMetric(c.descs["guest"], prometheus.CounterValue, cpu.Guest, append(cpuLabel, "user")...)
+	ch <- prometheus.MustNewConstMetric(c.descs["guest"], prometheus.CounterValue, cpu.GuestNice, append(cpuLabel, "nice")...)
 }
 
-func (c *memoryCollector) Describe(ch chan<- *prometheus.Desc) {
-	c.descs = map[string]*prometheus.Desc{
-		"MemTotal":     prometheus.NewDesc("node_memory_MemTotal_bytes", "Memory information field MemTotal_bytes.", nil, nil),
-		"MemFree":      prometheus.NewDesc("node_memory_MemFree_bytes", "Memory information field MemFree_bytes.", nil, nil),
-		"MemAvailable": prometheus.NewDesc("node_memory_MemAvailable_bytes", "Memory information field MemAvailable_bytes.", nil, nil),
-		"Buffers":      prometheus.NewDesc("node_memory_Buffers_bytes", "Memory information field Buffers_bytes.", nil, nil),
-		"Cached":       prometheus.NewDesc("node_memory_Cached_bytes", "Memory information field Cached_bytes.", nil, nil),
-		"SwapTotal":    prometheus.NewDesc("node_memory_SwapTotal_bytes", "Memory information field SwapTotal_bytes.", nil, nil),
-		"SwapFree":     prometheus.NewDesc("node_memory_SwapFree_bytes", "Memory information field SwapFree_bytes.", nil, nil),
-	}
+// collectOnlineState reports the online CPU count and per-CPU online state from sysfs
+func (c *cpuCollector) collectOnlineState(ch chan<- prometheus.Metric) {
+	cpuDir := filepath.Join(c.sysPath, "devices", "system", "cpu")
 
-	for _, desc := range c.descs {
-		ch <- desc
+	present, err := readCPUList(filepath.Join(cpuDir, "present"))
+	if err != nil {
+		c.logger.Debug("Failed to get present CPUs", zap.Error(err))
+		return
 	}
-}
 
-func (c *memoryCollector) Collect(ch chan<- prometheus.Metric) {
-	meminfo, err := c.procFS.Meminfo()
+	online, err := readCPUList(filepath.Join(cpuDir, "online"))
 	if err != nil {
-		c.logger.Debug("Failed to get memory info", zap.Error(err))
+		c.logger.Debug("Failed to get online CPUs", zap.Error(err))
 		return
 	}
 
-	if meminfo.MemTotal != nil {
-		ch <- prometheus.MustNewConstMetric(c.descs["MemTotal"], prometheus.GaugeValue, float64(*meminfo.MemTotal*1024))
+	onlineSet := make(map[int]bool, len(online))
+	for _, cpu := range online {
+		onlineSet[cpu] = true
 	}
-	if meminfo.MemFree != nil {
-		ch <- prometheus.MustNewConstMetric(c.descs["MemFree"], prometheus.GaugeValue, float64(*meminfo.MemFree*1024))
+
+	ch <- prometheus.MustNewConstMetric(c.descs["count"], prometheus.GaugeValue, float64(len(online)))
+
+	for _, cpu := range present {
+		state := 0.0
+		if onlineSet[cpu] {
+			state = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.descs["online"], prometheus.GaugeValue, state, strconv.Itoa(cpu))
 	}
-	if meminfo.MemAvailable != nil {
-		ch <- prometheus.MustNewConstMetric(c.descs["MemAvailable"], prometheus.GaugeValue, float64(*meminfo.MemAvailable*1024))
+}
+
+// defaultMemoryInclude selects the /proc/meminfo fields expected by the ingestor whitelist
+const defaultMemoryInclude = `^(MemTotal|MemFree|MemAvailable|Buffers|Cached|SwapTotal|SwapFree)$`
+
+// memoryOptions configures the memory collector with regular expressions over
+// /proc/meminfo field names
+type memoryOptions struct {
+	Include string `yaml:"include" json:"include"`
+	Exclude string `yaml:"exclude" json:"exclude"`
+}
+
+func newMemoryOptions() config.CollectorOptions {
+	return &memoryOptions{Include: defaultMemoryInclude}
+}
+
+func (o *memoryOptions) Validate() error {
+	if err := validatePattern("include", o.Include); err != nil {
+		return err
 	}
-	if meminfo.Buffers != nil {
-		ch <- prometheus.MustNewConstMetric(c.descs["Buffers"], prometheus.GaugeValue, float64(*meminfo.Buffers*1024))
+	return validatePattern("exclude", o.Exclude)
+}
+
+type memoryCollector struct {
+	procPath string
+	include  *regexp.Regexp
+	exclude  *regexp.Regexp
+	logger   *zap.Logger
+}
+
+// newMemoryCollector creates a /proc/meminfo collector reporting the fields that
+// match include and do not match exclude; an empty include selects the default fields
+func newMemoryCollector(procPath, include, exclude string, logger *zap.Logger) (*memoryCollector, error) {
+	if include == "" {
+		include = defaultMemoryInclude
 	}
-	if meminfo.Cached != nil {
-		ch <- prometheus.MustNewConstMetric(c.descs["Cached"], prometheus.GaugeValue, float64(*meminfo.Cached*1024))
+
+	c := &memoryCollector{procPath: procPath, logger: logger}
+
+	var err error
+	if c.include, err = regexp.Compile(include); err != nil {
+		return nil, fmt.Errorf("invalid memory include pattern %q: %w", include, err)
 	}
-	if meminfo.SwapTotal != nil {
-		ch <- prometheus.MustNewConstMetric(c.descs["SwapTotal"], prometheus.GaugeValue, float64(*meminfo.SwapTotal*1024))
+	if exclude != "" {
+		if c.exclude, err = regexp.Compile(exclude); err != nil {
+			return nil, fmt.Errorf("invalid memory exclude pattern %q: %w", exclude, err)
+		}
 	}
-	if meminfo.SwapFree != nil {
-		ch <- prometheus.MustNewConstMetric(c.descs["SwapFree"], prometheus.GaugeValue, float64(*meminfo.SwapFree*1024))
+
+	return c, nil
+}
+
+// Describe sends no descriptors: the metric set depends on the fields the
+// running kernel exposes, so the collector is registered as unchecked
+func (c *memoryCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func (c *memoryCollector) collect(ch chan<- prometheus.Metric) error {
+	file, err := os.Open(filepath.Join(c.procPath, "meminfo"))
+	if err != nil {
+		return fmt.Errorf("failed to get memory info: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Lines look like "MemTotal:  16318412 kB" or "HugePages_Total:  0"
+		parts := strings.Fields(scanner.Text())
+		if len(parts) < 2 {
+			continue
+		}
+
+		field := meminfoFieldName(parts[0])
+		if !c.include.MatchString(field) || (c.exclude != nil && c.exclude.MatchString(field)) {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			c.logger.Debug("Failed to parse meminfo field", zap.String("field", field), zap.Error(err))
+			continue
+		}
+
+		// Sizes are reported in kB; unitless fields such as HugePages_Total are counts
+		name := field
+		if len(parts) == 3 && parts[2] == "kB" {
+			value *= 1024
+			name += "_bytes"
+		}
+
+		desc := prometheus.NewDesc(
+			"node_memory_"+name,
+			fmt.Sprintf("Memory information field %s.", name),
+			nil, nil,
+		)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read memory info: %w", err)
 	}
+	return nil
+}
+
+// meminfoFieldName turns a meminfo key such as "Active(anon):" into a metric-safe "Active_anon"
+func meminfoFieldName(key string) string {
+	key = strings.TrimSuffix(key, ":")
+	key = strings.ReplaceAll(key, "(", "_")
+	return strings.ReplaceAll(key, ")", "")
 }
 
 type loadAvgCollector struct {
@@ -349,30 +661,101 @@ func (c *loadAvgCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
-func (c *loadAvgCollector) Collect(ch chan<- prometheus.Metric) {
+func (c *loadAvgCollector) collect(ch chan<- prometheus.Metric) error {
 	loadavg, err := c.procFS.LoadAvg()
 	if err != nil {
-		c.logger.Debug("Failed to get load average", zap.Error(err))
-		return
+		return fmt.Errorf("failed to get load average: %w", err)
 	}
 
 	ch <- prometheus.MustNewConstMetric(c.descs["load1"], prometheus.GaugeValue, loadavg.Load1)
 	ch <- prometheus.MustNewConstMetric(c.descs["load5"], prometheus.GaugeValue, loadavg.Load5)
 	ch <- prometheus.MustNewConstMetric(c.descs["load15"], prometheus.GaugeValue, loadavg.Load15)
+	return nil
+}
+
+// defaultDiskStatsDeviceExclude skips RAM disks, loop devices and floppy drives
+const defaultDiskStatsDeviceExclude = `^(z?ram|loop|fd)\d+$`
+
+// diskStatsOptions configures the diskstats collector
+type diskStatsOptions struct {
+	// Extended adds merged I/Os, I/O times, in-flight I/Os and discard/flush counters
+	Extended      bool   `yaml:"extended" json:"extended"`
+	DeviceInclude string `yaml:"device_include" json:"device_include"`
+	DeviceExclude string `yaml:"device_exclude" json:"device_exclude"`
+}
+
+func newDiskStatsOptions() config.CollectorOptions {
+	return &diskStatsOptions{Extended: true, DeviceExclude: defaultDiskStatsDeviceExclude}
+}
+
+func (o *diskStatsOptions) Validate() error {
+	if err := validatePattern("device_include", o.DeviceInclude); err != nil {
+		return err
+	}
+	return validatePattern("device_exclude", o.DeviceExclude)
 }
 
 type diskStatsCollector struct {
-	procFS procfs.FS
-	logger *zap.Logger
-	descs  map[string]*prometheus.Desc
+	procPath      string
+	sysPath       string
+	devices       *blockDeviceResolver
+	extended      bool
+	deviceInclude *regexp.Regexp
+	deviceExclude *regexp.Regexp
+	logger        *zap.Logger
+	descs         map[string]*prometheus.Desc
+}
+
+// newDiskStatsCollector creates a disk I/O collector. Devices backing a mount are
+// always candidates; deviceInclude adds unmounted devices and deviceExclude removes
+// devices from either set. Empty patterns are ignored.
+func newDiskStatsCollector(procPath, sysPath string, devices *blockDeviceResolver, extended bool, deviceInclude, deviceExclude string, logger *zap.Logger) (*diskStatsCollector, error) {
+	c := &diskStatsCollector{
+		procPath: procPath,
+		sysPath:  sysPath,
+		devices:  devices,
+		extended: extended,
+		logger:   logger,
+	}
+
+	var err error
+	if deviceInclude != "" {
+		if c.deviceInclude, err = regexp.Compile(deviceInclude); err != nil {
+			return nil, fmt.Errorf("invalid disk device include pattern %q: %w", deviceInclude, err)
+		}
+	}
+	if deviceExclude != "" {
+		if c.deviceExclude, err = regexp.Compile(deviceExclude); err != nil {
+			return nil, fmt.Errorf("invalid disk device exclude pattern %q: %w", deviceExclude, err)
+		}
+	}
+
+	return c, nil
 }
 
 func (c *diskStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	labels := []string{"device", "dm_name", "lv"}
 	c.descs = map[string]*prometheus.Desc{
-		"reads":      prometheus.NewDesc("node_disk_reads_completed_total", "The total number of reads completed successfully.", []string{"device"}, nil),
-		"writes":     prometheus.NewDesc("node_disk_writes_completed_total", "The total number of writes completed successfully.", []string{"device"}, nil),
-		"read_bytes": prometheus.NewDesc("node_disk_read_bytes_total", "The total number of bytes read successfully.", []string{"device"}, nil),
-		"write_bytes": prometheus.NewDesc("node_disk_written_bytes_total", "The total number of bytes written successfully.", []string{"device"}, nil),
+		"reads":      prometheus.NewDesc("node_disk_reads_completed_total", "The total number of reads completed successfully.", labels, nil),
+		"writes":     prometheus.NewDesc("node_disk_writes_completed_total", "The total number of writes completed successfully.", labels, nil),
+		"read_bytes": prometheus.NewDesc("node_disk_read_bytes_total", "The total number of bytes read successfully.", labels, nil),
+		"write_bytes": prometheus.NewDesc("node_disk_written_bytes_total", "The total number of bytes written successfully.", labels, nil),
+	}
+
+	if c.extended {
+		c.descs["reads_merged"] = prometheus.NewDesc("node_disk_reads_merged_total", "The total number of reads merged.", labels, nil)
+		c.descs["writes_merged"] = prometheus.NewDesc("node_disk_writes_merged_total", "The total number of writes merged.", labels, nil)
+		c.descs["read_time"] = prometheus.NewDesc("node_disk_read_time_seconds_total", "The total number of seconds spent by all reads.", labels, nil)
+		c.descs["write_time"] = prometheus.NewDesc("node_disk_write_time_seconds_total", "The total number of seconds spent by all writes.", labels, nil)
+		c.descs["io_now"] = prometheus.NewDesc("node_disk_io_now", "The number of I/Os currently in progress.", labels, nil)
+		c.descs["io_time"] = prometheus.NewDesc("node_disk_io_time_seconds_total", "Total seconds spent doing I/Os.", labels, nil)
+		c.descs["io_time_weighted"] = prometheus.NewDesc("node_disk_io_time_weighted_seconds_total", "The weighted number of seconds spent doing I/Os.", labels, nil)
+		c.descs["discards"] = prometheus.NewDesc("node_disk_discards_completed_total", "The total number of discards completed successfully.", labels, nil)
+		c.descs["discards_merged"] = prometheus.NewDesc("node_disk_discards_merged_total", "The total number of discards merged.", labels, nil)
+		c.descs["discarded_sectors"] = prometheus.NewDesc("node_disk_discarded_sectors_total", "The total number of sectors discarded successfully.", labels, nil)
+		c.descs["discard_time"] = prometheus.NewDesc("node_disk_discard_time_seconds_total", "The total number of seconds spent by all discards.", labels, nil)
+		c.descs["flushes"] = prometheus.NewDesc("node_disk_flush_requests_total", "The total number of flush requests completed successfully.", labels, nil)
+		c.descs["flush_time"] = prometheus.NewDesc("node_disk_flush_requests_time_seconds_total", "The total number of seconds spent by all flush requests.", labels, nil)
 	}
 
 	for _, desc := range c.descs {
@@ -380,15 +763,42 @@ func (c *diskStatsCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
-func (c *diskStatsCollector) Collect(ch chan<- prometheus.Metric) {
+func (c *diskStatsCollector) collect(ch chan<- prometheus.Metric) error {
 	// Get mounted devices to filter disk stats
-	mounts, err := procfs.GetMounts()
+	mounts, err := readMounts(c.procPath)
 	if err != nil {
-		c.logger.Debug("Failed to get mount information", zap.Error(err))
-		return
+		return fmt.Errorf("failed to get mount information: %w", err)
+	}
+
+	devices := c.devices.load()
+	validDevices := c.mountedDevices(mounts, devices)
+
+	// Use blockdevice package to get disk stats
+	blockFS, err := blockdevice.NewFS(c.procPath, c.sysPath)
+	if err != nil {
+		return fmt.Errorf("failed to initialize blockdevice FS: %w", err)
+	}
+
+	diskStats, err := blockFS.ProcDiskstats()
+	if err != nil {
+		return fmt.Errorf("failed to get disk stats: %w", err)
+	}
+
+	for _, stat := range diskStats {
+		if !c.reportDevice(stat.DeviceName, validDevices) {
+			c.logger.Debug("Skipping device - not mounted or included",
+				zap.String("device", stat.DeviceName))
+			continue
+		}
+
+		dmName, lv := devices.labels(stat.DeviceName)
+		c.emitDiskStats(ch, stat, stat.DeviceName, dmName, lv)
 	}
+	return nil
+}
 
-	// Build a set of valid device names from mounts
+// mountedDevices returns the kernel names of the block devices backing real filesystems
+func (c *diskStatsCollector) mountedDevices(mounts []*procfs.MountInfo, devices *blockDevices) map[string]bool {
 	validDevices := make(map[string]bool)
 	for _, mount := range mounts {
 		if ignoredFSTypes[mount.FSType] {
@@ -403,51 +813,148 @@ func (c *diskStatsCollector) Collect(ch chan<- prometheus.Metric) {
 			continue
 		}
 
-		// Extract device name without /dev/ prefix
-		deviceName := strings.TrimPrefix(mount.Source, "/dev/")
-		validDevices[deviceName] = true
+		// Resolve mapper and by-uuid/by-label sources to the /proc/diskstats name
+		validDevices[devices.kernelName(mount.Source)] = true
 	}
 
-	// Use blockdevice package to get disk stats
-	blockFS, err := blockdevice.NewFS("/proc", "/sys")
-	if err != nil {
-		c.logger.Debug("Failed to initialize blockdevice FS", zap.Error(err))
-		return
+	return validDevices
+}
+
+// reportDevice decides whether a /proc/diskstats entry is reported
+func (c *diskStatsCollector) reportDevice(device string, mounted map[string]bool) bool {
+	if c.deviceExclude != nil && c.deviceExclude.MatchString(device) {
+		return false
 	}
 
-	diskStats, err := blockFS.ProcDiskstats()
-	if err != nil {
-		c.logger.Debug("Failed to get disk stats", zap.Error(err))
+	return mounted[device] || (c.deviceInclude != nil && c.deviceInclude.MatchString(device))
+}
+
+// emitDiskStats sends the metrics of one /proc/diskstats entry
+func (c *diskStatsCollector) emitDiskStats(ch chan<- prometheus.Metric, stat blockdevice.Diskstats, labels ...string) {
+
+	ch <- prometheus.MustNewConstMetric(c.descs["reads"], prometheus.CounterValue, float64(stat.ReadIOs), labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["writes"], prometheus.CounterValue, float64(stat.WriteIOs), labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["read_bytes"], prometheus.CounterValue, float64(stat.ReadSectors*512), labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["write_bytes"], prometheus.CounterValue, float64(stat.WriteSectors*512), labels...)
+
+	if !c.extended {
 		return
 	}
 
-	for _, stat := range diskStats {
-		// Only collect metrics for valid mounted devices
-		if !validDevices[stat.DeviceName] {
-			c.logger.Debug("Skipping device - not in valid mounted devices",
-				zap.String("device", stat.DeviceName))
-			continue
-		}
+	// Time fields are reported by the kernel in milliseconds
+	ch <- prometheus.MustNewConstMetric(c.descs["reads_merged"], prometheus.CounterValue, float64(stat.ReadMerges), labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["writes_merged"], prometheus.CounterValue, float64(stat.WriteMerges), labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["read_time"], prometheus.CounterValue, float64(stat.ReadTicks)/1000, labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["write_time"], prometheus.CounterValue, float64(stat.WriteTicks)/1000, labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["io_now"], prometheus.GaugeValue, float64(stat.IOsInProgress), labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["io_time"], prometheus.CounterValue, float64(stat.IOsTotalTicks)/1000, labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["io_time_weighted"], prometheus.CounterValue, float64(stat.WeightedIOTicks)/1000, labels...)
+
+	// IoStatsCount includes the major, minor and name columns: discard fields
+	// arrived in kernel 4.18 (18 columns) and flush fields in 5.5 (20 columns)
+	if stat.IoStatsCount >= 18 {
+		ch <- prometheus.MustNewConstMetric(c.descs["discards"], prometheus.CounterValue, float64(stat.DiscardIOs), labels...)
+		ch <- prometheus.MustNewConstMetric(c.descs["discards_merged"], prometheus.CounterValue, float64(stat.DiscardMerges), labels...)
+		ch <- prometheus.MustNewConstMetric(c.descs["discarded_sectors"], prometheus.CounterValue, float64(stat.DiscardSectors), labels...)
+		ch <- prometheus.MustNewConstMetric(c.descs["discard_time"], prometheus.CounterValue, float64(stat.DiscardTicks)/1000, labels...)
+	}
+	if stat.IoStatsCount >= 20 {
+		ch <- prometheus.MustNewConstMetric(c.descs["flushes"], prometheus.CounterValue, float64(stat.FlushRequestsCompleted), labels...)
+		ch <- prometheus.MustNewConstMetric(c.descs["flush_time"], prometheus.CounterValue, float64(stat.TimeSpentFlushing)/1000, labels...)
+	}
+}
 
-		ch <- prometheus.MustNewConstMetric(c.descs["reads"], prometheus.CounterValue, float64(stat.ReadIOs), stat.DeviceName)
-		ch <- prometheus.MustNewConstMetric(c.descs["writes"], prometheus.CounterValue, float64(stat.WriteIOs), stat.DeviceName)
-		ch <- prometheus.MustNewConstMetric(c.descs["read_bytes"], prometheus.CounterValue, float64(stat.ReadSectors*512), stat.DeviceName)
-		ch <- prometheus.MustNewConstMetric(c.descs["write_bytes"], prometheus.CounterValue, float64(stat.WriteSectors*512), stat.DeviceName)
+// defaultNetDevDeviceExclude skips loopback and container plumbing interfaces
+const defaultNetDevDeviceExclude = `^(lo|veth.*|docker0|cni.*)$`
+
+// networkOptions configures the netdev collector
+type networkOptions struct {
+	// Extended adds error and drop counters and link metadata from sysfs
+	Extended bool `yaml:"extended" json:"extended"`
+	// AddressInfo adds node_network_address_info for every address. It is
+	// ignored unless the proc and sys paths are the defaults
+	AddressInfo   bool   `yaml:"address_info" json:"address_info"`
+	DeviceInclude string `yaml:"device_include" json:"device_include"`
+	DeviceExclude string `yaml:"device_exclude" json:"device_exclude"`
+}
+
+func newNetworkOptions() config.CollectorOptions {
+	return &networkOptions{Extended: true, AddressInfo: true, DeviceExclude: defaultNetDevDeviceExclude}
+}
+
+func (o *networkOptions) Validate() error {
+	if err := validatePattern("device_include", o.DeviceInclude); err != nil {
+		return err
 	}
+	return validatePattern("device_exclude", o.DeviceExclude)
 }
 
 type networkCollector struct {
-	procFS procfs.FS
-	logger *zap.Logger
-	descs  map[string]*prometheus.Desc
+	procFS        procfs.FS
+	sysPath       string
+	extended      bool
+	addressInfo   bool
+	addrs         func(device string) ([]net.Addr, error)
+	deviceInclude *regexp.Regexp
+	deviceExclude *regexp.Regexp
+	logger        *zap.Logger
+	descs         map[string]*prometheus.Desc
+}
+
+// newNetworkCollector creates a network device collector. When deviceInclude is
+// set only matching interfaces are reported; deviceExclude removes interfaces.
+// Empty patterns are ignored.
+func newNetworkCollector(procFS procfs.FS, sysPath string, extended, addressInfo bool, deviceInclude, deviceExclude string, logger *zap.Logger) (*networkCollector, error) {
+	c := &networkCollector{
+		procFS:      procFS,
+		sysPath:     sysPath,
+		extended:    extended,
+		addressInfo: addressInfo,
+		addrs:       interfaceAddrs,
+		logger:      logger,
+	}
+
+	var err error
+	if deviceInclude != "" {
+		if c.deviceInclude, err = regexp.Compile(deviceInclude); err != nil {
+			return nil, fmt.Errorf("invalid network device include pattern %q: %w", deviceInclude, err)
+		}
+	}
+	if deviceExclude != "" {
+		if c.deviceExclude, err = regexp.Compile(deviceExclude); err != nil {
+			return nil, fmt.Errorf("invalid network device exclude pattern %q: %w", deviceExclude, err)
+		}
+	}
+
+	return c, nil
 }
 
 func (c *networkCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.descs = map[string]*prometheus.Desc{
-		"receive_bytes":    prometheus.NewDesc("node_network_receive_bytes_total", "Network device statistic receive_bytes.", []string{"device", "ip_address"}, nil),
-		"transmit_bytes":   prometheus.NewDesc("node_network_transmit_bytes_total", "Network device statistic transmit_bytes.", []string{"device", "ip_address"}, nil),
-		"receive_packets":  prometheus.NewDesc("node_network_receive_packets_total", "Network device statistic receive_packets.", []string{"device", "ip_address"}, nil),
-		"transmit_packets": prometheus.NewDesc("node_network_transmit_packets_total", "Network device statistic transmit_packets.", []string{"device", "ip_address"}, nil),
+		"receive_bytes":    prometheus.NewDesc("node_network_receive_bytes_total", "Network device statistic receive_bytes.", []string{"device"}, nil),
+		"transmit_bytes":   prometheus.NewDesc("node_network_transmit_bytes_total", "Network device statistic transmit_bytes.", []string{"device"}, nil),
+		"receive_packets":  prometheus.NewDesc("node_network_receive_packets_total", "Network device statistic receive_packets.", []string{"device"}, nil),
+		"transmit_packets": prometheus.NewDesc("node_network_transmit_packets_total", "Network device statistic transmit_packets.", []string{"device"}, nil),
+	}
+
+	if c.extended {
+		for _, stat := range []string{
+			"receive_errs", "transmit_errs", "receive_drop", "transmit_drop",
+			"receive_fifo", "transmit_fifo", "receive_frame", "receive_compressed",
+			"transmit_compressed", "receive_multicast",
+		} {
+			c.descs[stat] = prometheus.NewDesc("node_network_"+stat+"_total", "Network device statistic "+stat+".", []string{"device"}, nil)
+		}
+
+		c.descs["up"] = prometheus.NewDesc("node_network_up", "Value is 1 if operstate is 'up', 0 otherwise.", []string{"device"}, nil)
+		c.descs["info"] = prometheus.NewDesc("node_network_info", "Network device operational state from /sys/class/net.", []string{"device", "operstate"}, nil)
+		c.descs["speed"] = prometheus.NewDesc("node_network_speed_bytes", "Network device link speed in bytes per second.", []string{"device"}, nil)
+		c.descs["mtu"] = prometheus.NewDesc("node_network_mtu_bytes", "Network device MTU in bytes.", []string{"device"}, nil)
+		c.descs["carrier_changes"] = prometheus.NewDesc("node_network_carrier_changes_total", "Number of times the link carrier changed state.", []string{"device"}, nil)
+	}
+
+	if c.addressInfo {
+		c.descs["address_info"] = prometheus.NewDesc("node_network_address_info", "Address assigned to a network device, value is always 1.", []string{"device", "address", "family", "scope"}, nil)
 	}
 
 	for _, desc := range c.descs {
@@ -455,47 +962,203 @@ func (c *networkCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
-func (c *networkCollector) Collect(ch chan<- prometheus.Metric) {
+func (c *networkCollector) collect(ch chan<- prometheus.Metric) error {
 	netDev, err := c.procFS.NetDev()
 	if err != nil {
-		c.logger.Debug("Failed to get network stats", zap.Error(err))
-		return
+		return fmt.Errorf("failed to get network stats: %w", err)
 	}
 
 	for _, dev := range netDev {
-		// Get IP address for this interface
-		ipAddress, err := getInterfaceIPAddress(dev.Name)
-		if err != nil {
-			c.logger.Debug("Failed to get IP address for interface",
-				zap.String("interface", dev.Name),
-				zap.Error(err))
-			ipAddress = "none"
+		if !c.reportDevice(dev.Name) {
+			continue
 		}
 
 		c.logger.Debug("Collecting network metrics",
 			zap.String("interface", dev.Name),
-			zap.String("ip_address", ipAddress),
 			zap.Uint64("rx_bytes", dev.RxBytes),
 			zap.Uint64("tx_bytes", dev.TxBytes))
 
-		ch <- prometheus.MustNewConstMetric(c.descs["receive_bytes"], prometheus.CounterValue, float64(dev.RxBytes), dev.Name, ipAddress)
-		ch <- prometheus.MustNewConstMetric(c.descs["transmit_bytes"], prometheus.CounterValue, float64(dev.TxBytes), dev.Name, ipAddress)
-		ch <- prometheus.MustNewConstMetric(c.descs["receive_packets"], prometheus.CounterValue, float64(dev.RxPackets), dev.Name, ipAddress)
-		ch <- prometheus.MustNewConstMetric(c.descs["transmit_packets"], prometheus.CounterValue, float64(dev.TxPackets), dev.Name, ipAddress)
+		ch <- prometheus.MustNewConstMetric(c.descs["receive_bytes"], prometheus.CounterValue, float64(dev.RxBytes), dev.Name)
+		ch <- prometheus.MustNewConstMetric(c.descs["transmit_bytes"], prometheus.CounterValue, float64(dev.TxBytes), dev.Name)
+		ch <- prometheus.MustNewConstMetric(c.descs["receive_packets"], prometheus.CounterValue, float64(dev.RxPackets), dev.Name)
+		ch <- prometheus.MustNewConstMetric(c.descs["transmit_packets"], prometheus.CounterValue, float64(dev.TxPackets), dev.Name)
+
+		if c.extended {
+			c.emitDeviceErrors(ch, dev)
+			c.emitLinkInfo(ch, dev.Name)
+		}
+		if c.addressInfo {
+			c.emitAddressInfo(ch, dev.Name)
+		}
+	}
+	return nil
+}
+
+// emitAddressInfo sends one info series per address assigned to the device.
+// Addresses are kept out of the counter labels so that address changes do
+// not create new counter series.
+func (c *networkCollector) emitAddressInfo(ch chan<- prometheus.Metric, device string) {
+	addrs, err := c.addrs(device)
+	if err != nil {
+		c.logger.Debug("Failed to get addresses for interface",
+			zap.String("interface", device),
+			zap.Error(err))
+		return
 	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+
+		family := "inet6"
+		if ipNet.IP.To4() != nil {
+			family = "inet"
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.descs["address_info"], prometheus.GaugeValue, 1,
+			device, ipNet.IP.String(), family, addressScope(ipNet.IP))
+	}
+}
+
+// reportDevice applies the interface include and exclude patterns
+func (c *networkCollector) reportDevice(device string) bool {
+	if c.deviceInclude != nil && !c.deviceInclude.MatchString(device) {
+		return false
+	}
+
+	return c.deviceExclude == nil || !c.deviceExclude.MatchString(device)
+}
+
+// emitDeviceErrors sends the error, drop and miscellaneous counters of /proc/net/dev
+func (c *networkCollector) emitDeviceErrors(ch chan<- prometheus.Metric, dev procfs.NetDevLine) {
+	counters := map[string]uint64{
+		"receive_errs":        dev.RxErrors,
+		"transmit_errs":       dev.TxErrors,
+		"receive_drop":        dev.RxDropped,
+		"transmit_drop":       dev.TxDropped,
+		"receive_fifo":        dev.RxFIFO,
+		"transmit_fifo":       dev.TxFIFO,
+		"receive_frame":       dev.RxFrame,
+		"receive_compressed":  dev.RxCompressed,
+		"transmit_compressed": dev.TxCompressed,
+		"receive_multicast":   dev.RxMulticast,
+	}
+
+	for stat, value := range counters {
+		ch <- prometheus.MustNewConstMetric(c.descs[stat], prometheus.CounterValue, float64(value), dev.Name)
+	}
+}
+
+// emitLinkInfo sends link metadata from /sys/class/net/<device>. Virtual
+// interfaces have no link speed and down links refuse to report one, so
+// attributes that cannot be read are skipped.
+func (c *networkCollector) emitLinkInfo(ch chan<- prometheus.Metric, device string) {
+	deviceDir := filepath.Join(c.sysPath, "class", "net", device)
+
+	if data, err := os.ReadFile(filepath.Join(deviceDir, "operstate")); err == nil {
+		operstate := strings.TrimSpace(string(data))
+		up := 0.0
+		if operstate == "up" {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.descs["up"], prometheus.GaugeValue, up, device)
+		ch <- prometheus.MustNewConstMetric(c.descs["info"], prometheus.GaugeValue, 1, device, operstate)
+	}
+
+	// speed is reported in Mbit/s
+	if speed, err := readUintFromFile(filepath.Join(deviceDir, "speed")); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["speed"], prometheus.GaugeValue, float64(speed)*1000*1000/8, device)
+	}
+	if mtu, err := readUintFromFile(filepath.Join(deviceDir, "mtu")); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["mtu"], prometheus.GaugeValue, float64(mtu), device)
+	}
+	if changes, err := readUintFromFile(filepath.Join(deviceDir, "carrier_changes")); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["carrier_changes"], prometheus.CounterValue, float64(changes), device)
+	}
+}
+
+// defaultFilesystemStatfsTimeout bounds a statfs call before the mount is treated as hung
+const defaultFilesystemStatfsTimeout = 5 * time.Second
+
+// filesystemOptions configures the filesystem collector
+type filesystemOptions struct {
+	// Extended adds inode counts, read-only state and device errors
+	Extended bool `yaml:"extended" json:"extended"`
+	// StatfsTimeout bounds each statfs call before the mount is treated as hung
+	StatfsTimeout time.Duration `yaml:"statfs_timeout" json:"statfs_timeout"`
+}
+
+func newFilesystemOptions() config.CollectorOptions {
+	return &filesystemOptions{Extended: true, StatfsTimeout: defaultFilesystemStatfsTimeout}
+}
+
+func (o *filesystemOptions) Validate() error {
+	if o.StatfsTimeout <= 0 {
+		return fmt.Errorf("statfs_timeout must be positive")
+	}
+	return nil
+}
+
+// filesystemStatfsWorkers bounds the number of statfs calls in flight per collection
+const filesystemStatfsWorkers = 4
+
+// filesystemMount is a mount queued for a statfs worker, with its metric labels
+type filesystemMount struct {
+	mount  *procfs.MountInfo
+	labels []string
 }
 
 type filesystemCollector struct {
-	procFS procfs.FS
-	logger *zap.Logger
-	descs  map[string]*prometheus.Desc
+	procPath      string
+	rootPath      string
+	devices       *blockDeviceResolver
+	extended      bool
+	statfsTimeout time.Duration
+	statfs        func(path string, stat *syscall.Statfs_t) error
+	logger        *zap.Logger
+	descs         map[string]*prometheus.Desc
+
+	// stuckMounts holds mountpoints whose statfs call timed out and has not
+	// returned yet; they are skipped until the pending call completes
+	stuckMu     sync.Mutex
+	stuckMounts map[string]bool
+}
+
+// newFilesystemCollector creates a filesystem collector for the mounts of the
+// host whose root filesystem is visible at rootPath, falling back to the
+// default statfs timeout when none is set
+func newFilesystemCollector(procPath, rootPath string, devices *blockDeviceResolver, extended bool, statfsTimeout time.Duration, logger *zap.Logger) *filesystemCollector {
+	if statfsTimeout <= 0 {
+		statfsTimeout = defaultFilesystemStatfsTimeout
+	}
+
+	return &filesystemCollector{
+		procPath:      procPath,
+		rootPath:      rootPath,
+		devices:       devices,
+		extended:      extended,
+		statfsTimeout: statfsTimeout,
+		statfs:        syscall.Statfs,
+		logger:        logger,
+		stuckMounts:   make(map[string]bool),
+	}
 }
 
 func (c *filesystemCollector) Describe(ch chan<- *prometheus.Desc) {
+	labels := []string{"device", "fstype", "mountpoint", "dm_name", "lv"}
 	c.descs = map[string]*prometheus.Desc{
-		"size":  prometheus.NewDesc("node_filesystem_size_bytes", "Filesystem size in bytes.", []string{"device", "fstype", "mountpoint"}, nil),
-		"free":  prometheus.NewDesc("node_filesystem_free_bytes", "Filesystem free space in bytes.", []string{"device", "fstype", "mountpoint"}, nil),
-		"avail": prometheus.NewDesc("node_filesystem_avail_bytes", "Filesystem space available to non-root users in bytes.", []string{"device", "fstype", "mountpoint"}, nil),
+		"size":  prometheus.NewDesc("node_filesystem_size_bytes", "Filesystem size in bytes.", labels, nil),
+		"free":  prometheus.NewDesc("node_filesystem_free_bytes", "Filesystem free space in bytes.", labels, nil),
+		"avail": prometheus.NewDesc("node_filesystem_avail_bytes", "Filesystem space available to non-root users in bytes.", labels, nil),
+	}
+
+	if c.extended {
+		c.descs["files"] = prometheus.NewDesc("node_filesystem_files", "Filesystem total file nodes.", labels, nil)
+		c.descs["files_free"] = prometheus.NewDesc("node_filesystem_files_free", "Filesystem total free file nodes.", labels, nil)
+		c.descs["readonly"] = prometheus.NewDesc("node_filesystem_readonly", "Filesystem read-only status.", labels, nil)
+		c.descs["device_error"] = prometheus.NewDesc("node_filesystem_device_error", "Whether an error occurred while getting statistics for the given device.", labels, nil)
 	}
 
 	for _, desc := range c.descs {
@@ -503,11 +1166,24 @@ func (c *filesystemCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
-func (c *filesystemCollector) Collect(ch chan<- prometheus.Metric) {
-	mounts, err := procfs.GetMounts()
+func (c *filesystemCollector) collect(ch chan<- prometheus.Metric) error {
+	mounts, err := readMounts(c.procPath)
 	if err != nil {
-		c.logger.Debug("Failed to get mount information", zap.Error(err))
-		return
+		return fmt.Errorf("failed to get mount information: %w", err)
+	}
+
+	devices := c.devices.load()
+
+	jobs := make(chan filesystemMount)
+	var wg sync.WaitGroup
+	for i := 0; i < filesystemStatfsWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				c.emitMount(ch, job.mount, job.labels...)
+			}
+		}()
 	}
 
 	for _, mount := range mounts {
@@ -523,20 +1199,121 @@ func (c *filesystemCollector) Collect(ch chan<- prometheus.Metric) {
 			continue
 		}
 
-		var stat syscall.Statfs_t
-		if err := syscall.Statfs(mount.MountPoint, &stat); err != nil {
-			c.logger.Debug("Failed to get filesystem stats",
-				zap.String("mountpoint", mount.MountPoint),
-				zap.Error(err))
-			continue
+		dmName, lv := devices.labels(devices.kernelName(mount.Source))
+		jobs <- filesystemMount{mount: mount, labels: []string{mount.Source, mount.FSType, mount.MountPoint, dmName, lv}}
+	}
+
+	close(jobs)
+	wg.Wait()
+	return nil
+}
+
+// statMount runs statfs on a mountpoint without letting a hung filesystem block
+// the collection. A call that outlives statfsTimeout keeps running in the
+// background and the mountpoint is skipped until it returns
+func (c *filesystemCollector) statMount(mountPoint string) (*syscall.Statfs_t, error) {
+	c.stuckMu.Lock()
+	if c.stuckMounts[mountPoint] {
+		c.stuckMu.Unlock()
+		return nil, fmt.Errorf("statfs on %s has not returned since an earlier timeout", mountPoint)
+	}
+	c.stuckMu.Unlock()
+
+	type statfsResult struct {
+		stat syscall.Statfs_t
+		err  error
+	}
+	result := make(chan statfsResult, 1)
+
+	go func() {
+		var res statfsResult
+		res.err = c.statfs(mountPoint, &res.stat)
+		result <- res
+
+		c.stuckMu.Lock()
+		defer c.stuckMu.Unlock()
+		if c.stuckMounts[mountPoint] {
+			delete(c.stuckMounts, mountPoint)
+			c.logger.Info("Filesystem statfs returned after timing out", zap.String("mountpoint", mountPoint))
+		}
+	}()
+
+	timer := time.NewTimer(c.statfsTimeout)
+	defer timer.Stop()
+
+	var res statfsResult
+	select {
+	case res = <-result:
+	case <-timer.C:
+		// The call may have finished while the timer fired; only a call that is
+		// still pending under the lock is marked stuck, so it is always cleared
+		c.stuckMu.Lock()
+		select {
+		case res = <-result:
+			c.stuckMu.Unlock()
+		default:
+			c.stuckMounts[mountPoint] = true
+			c.stuckMu.Unlock()
+
+			c.logger.Warn("Filesystem statfs timed out, skipping mountpoint until it responds",
+				zap.String("mountpoint", mountPoint),
+				zap.Duration("timeout", c.statfsTimeout))
+			return nil, fmt.Errorf("statfs on %s timed out after %s", mountPoint, c.statfsTimeout)
+		}
+	}
+
+	if res.err != nil {
+		return nil, res.err
+	}
+	return &res.stat, nil
+}
+
+// emitMount sends the statfs results of one mount. The read-only state comes
+// from the mount and superblock options, so it is reported even when statfs
+// fails or times out; the failure itself is reported through device_error
+// instead of dropping the mount
+func (c *filesystemCollector) emitMount(ch chan<- prometheus.Metric, mount *procfs.MountInfo, labels ...string) {
+	// Mountpoints are host paths; stat them through the host root, keeping the
+	// host path in the mountpoint label
+	stat, statErr := c.statMount(filepath.Join(c.rootPath, mount.MountPoint))
+	if statErr != nil {
+		c.logger.Debug("Failed to get filesystem stats",
+			zap.String("mountpoint", mount.MountPoint),
+			zap.Error(statErr))
+	}
+
+	if c.extended {
+		// A filesystem remounted read-only by the kernel after errors, such as
+		// ext4 with errors=remount-ro, is only flagged in the superblock options
+		readOnly := 0.0
+		_, mountRO := mount.Options["ro"]
+		_, superRO := mount.SuperOptions["ro"]
+		if mountRO || superRO {
+			readOnly = 1
+		}
+		deviceError := 0.0
+		if statErr != nil {
+			deviceError = 1
 		}
 
-		totalSize := float64(stat.Blocks * uint64(stat.Bsize))
-		freeSize := float64(stat.Bfree * uint64(stat.Bsize))
-		availSize := float64(stat.Bavail * uint64(stat.Bsize))
+		ch <- prometheus.MustNewConstMetric(c.descs["readonly"], prometheus.GaugeValue, readOnly, labels...)
+		ch <- prometheus.MustNewConstMetric(c.descs["device_error"], prometheus.GaugeValue, deviceError, labels...)
+	}
 
-		ch <- prometheus.MustNewConstMetric(c.descs["size"], prometheus.GaugeValue, totalSize, mount.Source, mount.FSType, mount.MountPoint)
-		ch <- prometheus.MustNewConstMetric(c.descs["free"], prometheus.GaugeValue, freeSize, mount.Source, mount.FSType, mount.MountPoint)
-		ch <- prometheus.MustNewConstMetric(c.descs["avail"], prometheus.GaugeValue, availSize, mount.Source, mount.FSType, mount.MountPoint)
+	if statErr != nil {
+		return
 	}
-}
\ No newline at end of file
+
+	totalSize := float64(stat.Blocks * uint64(stat.Bsize))
+	freeSize := float64(stat.Bfree * uint64(stat.Bsize))
+	availSize := float64(stat.Bavail * uint64(stat.Bsize))
+
+	ch <- prometheus.MustNewConstMetric(c.descs["size"], prometheus.GaugeValue, totalSize, labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["free"], prometheus.GaugeValue, freeSize, labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["avail"], prometheus.GaugeValue, availSize, labels...)
+
+	if c.extended {
+		ch <- prometheus.MustNewConstMetric(c.descs["files"], prometheus.GaugeValue, float64(stat.Files), labels...)
+		ch <- prometheus.MustNewConstMetric(c.descs["files_free"], prometheus.GaugeValue, float64(stat.Ffree), labels...)
+	}
+}
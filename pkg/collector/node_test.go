@@ -3,11 +3,19 @@ package collector
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/procfs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
@@ -26,6 +34,17 @@ func isLinuxWithProc() bool {
 	return err == nil
 }
 
+// collectorConfig returns the default collector configuration with only the
+// named collectors enabled and without self-metrics
+func collectorConfig(names ...string) config.CollectorConfig {
+	cfg := config.NewCollectorConfig()
+	for name, entry := range cfg.Entries {
+		entry.Enabled = slices.Contains(names, name)
+	}
+	cfg.SelfMetrics = false
+	return cfg
+}
+
 func TestNewSystemCollector(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	
@@ -40,29 +59,19 @@ func TestNewSystemCollector(t *testing.T) {
 	}{
 		{
 			name: "all collectors enabled",
-			config: config.CollectorConfig{
-				CPU:        true,
-				LoadAvg:    true,
-				Memory:     true,
-				DiskStats:  true,
-				Filesystem: true,
-				NetDev:     true,
-			},
+			config: collectorConfig("cpu", "loadavg", "memory", "diskstats", "filesystem", "netdev"),
 			expectError: !expectSuccess, // Success on Linux, error on non-Linux
 			expectedCollectors: 6,
 		},
 		{
 			name: "minimal config",
-			config: config.CollectorConfig{
-				CPU:    true,
-				Memory: true,
-			},
+			config: collectorConfig("cpu", "memory"),
 			expectError: !expectSuccess, // Success on Linux, error on non-Linux
 			expectedCollectors: 2,
 		},
 		{
 			name:        "no collectors enabled",
-			config:      config.CollectorConfig{},
+			config:      collectorConfig(),
 			expectError: true, // Always fails - no collectors enabled
 			expectedCollectors: 0,
 		},
@@ -70,7 +79,7 @@ func TestNewSystemCollector(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			collector, err := NewSystemCollector(tt.config, logger)
+			collector, err := NewSystemCollector(tt.config, DefaultPaths, logger)
 			
 			if tt.expectError {
 				assert.Error(t, err)
@@ -93,12 +102,10 @@ func TestSystemCollectorInterface(t *testing.T) {
 	var _ Collector = (*SystemCollector)(nil)
 	
 	// Create a minimal collector that should work even without /proc
-	cfg := config.CollectorConfig{
-		Memory: true,
-	}
+	cfg := collectorConfig("memory")
 	
 	// This will fail on non-Linux, but we can test the interface
-	collector, err := NewSystemCollector(cfg, logger)
+	collector, err := NewSystemCollector(cfg, DefaultPaths, logger)
 	if err != nil {
 		t.Skip(skipMessageNonLinux)
 	}
@@ -114,11 +121,9 @@ func TestSystemCollectorInterface(t *testing.T) {
 func TestSystemCollectorTimeout(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	
-	cfg := config.CollectorConfig{
-		CPU: true,
-	}
+	cfg := collectorConfig("cpu")
 	
-	collector, err := NewSystemCollector(cfg, logger)
+	collector, err := NewSystemCollector(cfg, DefaultPaths, logger)
 	if err != nil {
 		t.Skip(skipMessageNonLinux)
 	}
@@ -137,15 +142,9 @@ func TestSystemCollectorTimeout(t *testing.T) {
 func TestGetEnabledCollectors(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	
-	cfg := config.CollectorConfig{
-		CPU:      true,
-		Memory:   true,
-		LoadAvg:  true,
-		NetDev:   false,
-		DiskStats: false,
-	}
+	cfg := collectorConfig("cpu", "memory", "loadavg")
 	
-	collector, err := NewSystemCollector(cfg, logger)
+	collector, err := NewSystemCollector(cfg, DefaultPaths, logger)
 	if err != nil {
 		t.Skip(skipMessageNonLinux)
 	}
@@ -162,19 +161,17 @@ func TestGetEnabledCollectors(t *testing.T) {
 	assert.NotContains(t, enabled, "diskstats")
 	
 	// All enabled should be true
-	for name, isEnabled := range enabled {
-		assert.True(t, isEnabled, "Collector %s should be enabled", name)
+	for name, status := range enabled {
+		assert.True(t, status.Enabled, "Collector %s should be enabled", name)
 	}
 }
 
 func TestSystemCollectorClose(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	
-	cfg := config.CollectorConfig{
-		CPU: true,
-	}
+	cfg := collectorConfig("cpu")
 	
-	collector, err := NewSystemCollector(cfg, logger)
+	collector, err := NewSystemCollector(cfg, DefaultPaths, logger)
 	if err != nil {
 		t.Skip(skipMessageNonLinux)
 	}
@@ -197,21 +194,19 @@ func TestCollectorConfigValidation(t *testing.T) {
 	}{
 		{
 			name:        "empty config",
-			config:      config.CollectorConfig{},
+			config:      collectorConfig(),
 			expectError: true, // Always fails - no collectors enabled
 		},
 		{
 			name: "valid single collector",
-			config: config.CollectorConfig{
-				CPU: true,
-			},
+			config: collectorConfig("cpu"),
 			expectError: !expectLinuxSuccess, // Success on Linux with /proc, error otherwise
 		},
 	}
 	
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := NewSystemCollector(tt.config, logger)
+			_, err := NewSystemCollector(tt.config, DefaultPaths, logger)
 			
 			if tt.expectError {
 				assert.Error(t, err, "Expected an error for config: %s (platform support: %v)", tt.name, expectLinuxSuccess)
@@ -259,14 +254,11 @@ func TestCollectorMetricNames(t *testing.T) {
 
 func BenchmarkSystemCollectorCreation(b *testing.B) {
 	logger := zaptest.NewLogger(b)
-	cfg := config.CollectorConfig{
-		CPU:    true,
-		Memory: true,
-	}
+	cfg := collectorConfig("cpu", "memory")
 	
 	b.ResetTimer()
 	for b.Loop() {
-		collector, err := NewSystemCollector(cfg, logger)
+		collector, err := NewSystemCollector(cfg, DefaultPaths, logger)
 		if err == nil {
 			if closeErr := collector.Close(); closeErr != nil {
 				b.Errorf("Failed to close collector: %v", closeErr)
@@ -282,16 +274,16 @@ func TestMockCollectorBehavior(t *testing.T) {
 	// Since we can't test the actual collection on non-Linux systems,
 	// we test the configuration and setup logic
 	configs := []config.CollectorConfig{
-		{CPU: true},
-		{Memory: true},
-		{LoadAvg: true},
-		{NetDev: true},
-		{DiskStats: true},
+		collectorConfig("cpu"),
+		collectorConfig("memory"),
+		collectorConfig("loadavg"),
+		collectorConfig("netdev"),
+		collectorConfig("diskstats"),
 	}
 	
 	for i, cfg := range configs {
 		t.Run(fmt.Sprintf("config_%d", i), func(t *testing.T) {
-			_, err := NewSystemCollector(cfg, logger)
+			_, err := NewSystemCollector(cfg, DefaultPaths, logger)
 			// We expect an error on non-Linux systems, but the error should be about /proc, not about configuration
 			if err != nil {
 				assert.Contains(t, err.Error(), "proc", "Error should be about /proc filesystem, not configuration")
@@ -304,12 +296,9 @@ func TestCollectorRegistry(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 
 	// Test that collectors properly register their metrics
-	cfg := config.CollectorConfig{
-		CPU:    true,
-		Memory: true,
-	}
+	cfg := collectorConfig("cpu", "memory")
 
-	collector, err := NewSystemCollector(cfg, logger)
+	collector, err := NewSystemCollector(cfg, DefaultPaths, logger)
 	if err != nil {
 		t.Skip(skipMessageNonLinux)
 	}
@@ -327,3 +316,394 @@ func TestCollectorRegistry(t *testing.T) {
 	assert.Greater(t, len(enabled), 0, "Should have at least one enabled collector")
 }
 
+
+// gatherFromCollector registers a single collector on a fresh registry and
+// returns the gathered metric families keyed by name
+func gatherFromCollector(t *testing.T, c metricsCollector) map[string]*dto.MetricFamily {
+	t.Helper()
+
+	families, _ := gatherWithError(t, c)
+	return families
+}
+
+// gatherWithError gathers c like gatherFromCollector and also returns the
+// error of its collection
+func gatherWithError(t *testing.T, c metricsCollector) (map[string]*dto.MetricFamily, error) {
+	t.Helper()
+
+	adapter := &collectorAdapter{metricsCollector: c}
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(adapter))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	result := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		result[family.GetName()] = family
+	}
+	return result, adapter.lastError()
+}
+
+// metricValue returns the value of the metric in family whose labels match the given pairs
+func metricValue(t *testing.T, family *dto.MetricFamily, labels map[string]string) float64 {
+	t.Helper()
+	require.NotNil(t, family)
+
+	for _, metric := range family.Metric {
+		matched := 0
+		for _, pair := range metric.Label {
+			if value, ok := labels[pair.GetName()]; ok && value == pair.GetValue() {
+				matched++
+			}
+		}
+		if matched != len(labels) {
+			continue
+		}
+
+		switch {
+		case metric.Counter != nil:
+			return metric.Counter.GetValue()
+		case metric.Gauge != nil:
+			return metric.Gauge.GetValue()
+		case metric.Untyped != nil:
+			return metric.Untyped.GetValue()
+		}
+	}
+
+	t.Fatalf("no metric in %s matched labels %v", family.GetName(), labels)
+	return 0
+}
+
+// labelValue returns the value of the named label on metric, or an empty string
+func labelValue(metric *dto.Metric, name string) string {
+	for _, pair := range metric.Label {
+		if pair.GetName() == name {
+			return pair.GetValue()
+		}
+	}
+	return ""
+}
+
+// writeFixture writes content to path under root, creating parent directories
+func writeFixture(t *testing.T, root, path, content string) {
+	t.Helper()
+
+	fullPath := filepath.Join(root, path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+	require.NoError(t, os.WriteFile(fullPath, []byte(content), 0644))
+}
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := parseCPUList("0-3,5,7-8")
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 5, 7, 8}, cpus)
+
+	cpus, err = parseCPUList("")
+	require.NoError(t, err)
+	assert.Empty(t, cpus)
+
+	_, err = parseCPUList("0-x")
+	assert.Error(t, err)
+}
+
+func TestCPUCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "proc/stat", "cpu  300 10 150 3000 30 0 15 7 40 2\ncpu0 100 5 50 1000 10 0 5 2 20 1\ncpu1 200 5 100 2000 20 0 10 5 20 1\nbtime 1700000000\nprocesses 10\n")
+	writeFixture(t, root, "sys/devices/system/cpu/present", "0-2\n")
+	writeFixture(t, root, "sys/devices/system/cpu/online", "0-1\n")
+
+	procFS, err := procfs.NewFS(filepath.Join(root, "proc"))
+	require.NoError(t, err)
+	logger := zaptest.NewLogger(t)
+
+	t.Run("aggregate", func(t *testing.T) {
+		c := &cpuCollector{procFS: procFS, sysPath: filepath.Join(root, "sys"), logger: logger}
+		families := gatherFromCollector(t, c)
+
+		require.Len(t, families, 1)
+		assert.Len(t, families["node_cpu_seconds_total"].Metric, 8)
+		assert.Equal(t, float64(3), metricValue(t, families["node_cpu_seconds_total"], map[string]string{"mode": "user"}))
+		assert.Equal(t, 0.07, metricValue(t, families["node_cpu_seconds_total"], map[string]string{"mode": "steal"}))
+	})
+
+	t.Run("per cpu with extended metrics", func(t *testing.T) {
+		c := &cpuCollector{procFS: procFS, sysPath: filepath.Join(root, "sys"), perCPU: true, extended: true, logger: logger}
+		families := gatherFromCollector(t, c)
+
+		assert.Len(t, families["node_cpu_seconds_total"].Metric, 16)
+		assert.Equal(t, float64(2), metricValue(t, families["node_cpu_seconds_total"], map[string]string{"cpu": "1", "mode": "user"}))
+		assert.Equal(t, 0.2, metricValue(t, families["node_cpu_guest_seconds_total"], map[string]string{"cpu": "0", "mode": "user"}))
+		assert.Equal(t, 0.01, metricValue(t, families["node_cpu_guest_seconds_total"], map[string]string{"cpu": "1", "mode": "nice"}))
+		assert.Equal(t, float64(2), metricValue(t, families["node_cpu_count"], nil))
+		assert.Equal(t, float64(1), metricValue(t, families["node_cpu_online"], map[string]string{"cpu": "1"}))
+		assert.Equal(t, float64(0), metricValue(t, families["node_cpu_online"], map[string]string{"cpu": "2"}))
+	})
+}
+
+func TestMemoryCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "meminfo", `MemTotal:       16318412 kB
+MemFree:         1213068 kB
+MemAvailable:   10404036 kB
+Buffers:          523468 kB
+Cached:          8412296 kB
+SwapTotal:       2097148 kB
+SwapFree:        2097148 kB
+Active(anon):    3194368 kB
+Dirty:               412 kB
+Slab:             801204 kB
+HugePages_Total:       4
+Hugepagesize:       2048 kB
+`)
+	logger := zaptest.NewLogger(t)
+
+	t.Run("default fields", func(t *testing.T) {
+		c, err := newMemoryCollector(root, "", "", logger)
+		require.NoError(t, err)
+		families := gatherFromCollector(t, c)
+
+		assert.Len(t, families, 7)
+		assert.Equal(t, float64(16318412*1024), metricValue(t, families["node_memory_MemTotal_bytes"], nil))
+		assert.Equal(t, float64(2097148*1024), metricValue(t, families["node_memory_SwapFree_bytes"], nil))
+	})
+
+	t.Run("all fields with exclude", func(t *testing.T) {
+		c, err := newMemoryCollector(root, ".*", "^Slab$", logger)
+		require.NoError(t, err)
+		families := gatherFromCollector(t, c)
+
+		assert.Len(t, families, 11)
+		assert.Equal(t, float64(3194368*1024), metricValue(t, families["node_memory_Active_anon_bytes"], nil))
+		assert.Equal(t, float64(412*1024), metricValue(t, families["node_memory_Dirty_bytes"], nil))
+		assert.Equal(t, float64(4), metricValue(t, families["node_memory_HugePages_Total"], nil))
+		assert.NotContains(t, families, "node_memory_Slab_bytes")
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := newMemoryCollector(root, "", "(", logger)
+		assert.Error(t, err)
+	})
+}
+
+func TestDiskStatsCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "proc/diskstats", `   7       0 loop0 100 0 200 10 0 0 0 0 0 20 10 0 0 0 0
+ 252       0 vdz 5000 120 400000 3000 8000 300 900000 12000 2 9000 15000 10 0 80 5 600 70
+ 253       0 dm-9 4000 0 300000 2500 7000 0 800000 11000 1 8500 13500
+`)
+	writeFixture(t, root, "proc/1/mountinfo", "24 1 252:0 / / rw,relatime shared:1 - ext4 /dev/vdz rw\n")
+	logger := zaptest.NewLogger(t)
+
+	writeFixture(t, root, "sys/block/dm-9/dm/name", "vg--data-root\n")
+	writeFixture(t, root, "sys/block/dm-9/dm/uuid", "LVM-0123456789abcdef\n")
+	devices := newBlockDeviceResolver(root, filepath.Join(root, "sys"))
+
+	c, err := newDiskStatsCollector(filepath.Join(root, "proc"), filepath.Join(root, "sys"), devices, true, `^(vdz|dm-9|loop0)$`, `^loop\d+$`, logger)
+	require.NoError(t, err)
+	families := gatherFromCollector(t, c)
+
+	reads := families["node_disk_reads_completed_total"]
+	require.NotNil(t, reads)
+	assert.Len(t, reads.Metric, 2, "loop0 is excluded even though it is included")
+	assert.Equal(t, float64(5000), metricValue(t, reads, map[string]string{"device": "vdz"}))
+	assert.Equal(t, float64(120), metricValue(t, families["node_disk_reads_merged_total"], map[string]string{"device": "vdz"}))
+	assert.Equal(t, float64(3), metricValue(t, families["node_disk_read_time_seconds_total"], map[string]string{"device": "vdz"}))
+	assert.Equal(t, float64(2), metricValue(t, families["node_disk_io_now"], map[string]string{"device": "vdz"}))
+	assert.Equal(t, float64(9), metricValue(t, families["node_disk_io_time_seconds_total"], map[string]string{"device": "vdz"}))
+	assert.Equal(t, 13.5, metricValue(t, families["node_disk_io_time_weighted_seconds_total"], map[string]string{"device": "dm-9", "dm_name": "vg--data-root", "lv": "root"}))
+	assert.Equal(t, float64(4000), metricValue(t, reads, map[string]string{"device": "dm-9", "dm_name": "vg--data-root", "lv": "root"}))
+	assert.Equal(t, float64(5000), metricValue(t, reads, map[string]string{"device": "vdz", "dm_name": "", "lv": ""}))
+	assert.Equal(t, float64(10), metricValue(t, families["node_disk_discards_completed_total"], map[string]string{"device": "vdz"}))
+	assert.Equal(t, float64(600), metricValue(t, families["node_disk_flush_requests_total"], map[string]string{"device": "vdz"}))
+	assert.Len(t, families["node_disk_discards_completed_total"].Metric, 1, "older kernels report no discard fields")
+	assert.Len(t, families["node_disk_flush_requests_total"].Metric, 1, "older kernels report no flush fields")
+}
+
+func TestDiskStatsReportDevice(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "sys/block/dm-1/dm/name", "vg-home\n")
+	devices := newBlockDeviceResolver(root, filepath.Join(root, "sys"))
+
+	c, err := newDiskStatsCollector("/proc", "/sys", devices, false, `^dm-0$`, `^loop\d+$`, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	mounted := c.mountedDevices([]*procfs.MountInfo{
+		{Source: "/dev/vda1", FSType: "ext4"},
+		{Source: "/dev/loop3", FSType: "ext4"},
+		{Source: "/dev/mapper/vg-home", FSType: "xfs"},
+		{Source: "tmpfs", FSType: "tmpfs"},
+	}, devices.load())
+	assert.Equal(t, map[string]bool{"vda1": true, "loop3": true, "dm-1": true}, mounted)
+
+	assert.True(t, c.reportDevice("vda1", mounted))
+	assert.True(t, c.reportDevice("dm-0", mounted), "included devices need not be mounted")
+	assert.False(t, c.reportDevice("loop3", mounted), "exclude wins over mounts")
+	assert.False(t, c.reportDevice("vdb", mounted))
+}
+
+func TestNetworkCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "proc/net/dev", `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0: 5000000   4000  3    7    1     2          4         9  2000000    3000    5    6    8     0       0          11
+vethab12: 300     3    0    0    0     0          0         0      400       4    0    0    0     0       0          0
+`)
+	writeFixture(t, root, "sys/class/net/eth0/operstate", "up\n")
+	writeFixture(t, root, "sys/class/net/eth0/speed", "10000\n")
+	writeFixture(t, root, "sys/class/net/eth0/mtu", "1500\n")
+	writeFixture(t, root, "sys/class/net/eth0/carrier_changes", "2\n")
+	writeFixture(t, root, "sys/class/net/lo/operstate", "unknown\n")
+	writeFixture(t, root, "sys/class/net/lo/speed", "-1\n")
+
+	procFS, err := procfs.NewFS(filepath.Join(root, "proc"))
+	require.NoError(t, err)
+	logger := zaptest.NewLogger(t)
+
+	t.Run("extended with default exclude", func(t *testing.T) {
+		c, err := newNetworkCollector(procFS, filepath.Join(root, "sys"), true, false, "", defaultNetDevDeviceExclude, logger)
+		require.NoError(t, err)
+		families := gatherFromCollector(t, c)
+
+		require.Len(t, families["node_network_receive_bytes_total"].Metric, 1)
+		assert.Equal(t, "eth0", labelValue(families["node_network_receive_bytes_total"].Metric[0], "device"))
+
+		eth0 := map[string]string{"device": "eth0"}
+		assert.Equal(t, float64(3), metricValue(t, families["node_network_receive_errs_total"], eth0))
+		assert.Equal(t, float64(6), metricValue(t, families["node_network_transmit_drop_total"], eth0))
+		assert.Equal(t, float64(8), metricValue(t, families["node_network_transmit_fifo_total"], eth0))
+		assert.Equal(t, float64(2), metricValue(t, families["node_network_receive_frame_total"], eth0))
+		assert.Equal(t, float64(11), metricValue(t, families["node_network_transmit_compressed_total"], eth0))
+		assert.Equal(t, float64(9), metricValue(t, families["node_network_receive_multicast_total"], eth0))
+		assert.Equal(t, float64(1), metricValue(t, families["node_network_up"], eth0))
+		assert.Equal(t, float64(1), metricValue(t, families["node_network_info"], map[string]string{"device": "eth0", "operstate": "up"}))
+		assert.Equal(t, 1.25e9, metricValue(t, families["node_network_speed_bytes"], eth0))
+		assert.Equal(t, float64(1500), metricValue(t, families["node_network_mtu_bytes"], eth0))
+		assert.Equal(t, float64(2), metricValue(t, families["node_network_carrier_changes_total"], eth0))
+	})
+
+	t.Run("include pattern", func(t *testing.T) {
+		c, err := newNetworkCollector(procFS, filepath.Join(root, "sys"), false, false, "^(lo|veth.*)$", "^veth", logger)
+		require.NoError(t, err)
+		families := gatherFromCollector(t, c)
+
+		require.Len(t, families["node_network_receive_bytes_total"].Metric, 1)
+		assert.Equal(t, "lo", labelValue(families["node_network_receive_bytes_total"].Metric[0], "device"))
+		assert.NotContains(t, families, "node_network_receive_errs_total")
+	})
+
+	t.Run("address info", func(t *testing.T) {
+		c, err := newNetworkCollector(procFS, filepath.Join(root, "sys"), false, true, "^eth0$", "", logger)
+		require.NoError(t, err)
+		c.addrs = func(device string) ([]net.Addr, error) {
+			return []net.Addr{
+				&net.IPNet{IP: net.ParseIP("10.0.0.5"), Mask: net.CIDRMask(24, 32)},
+				&net.IPNet{IP: net.ParseIP("10.0.0.6"), Mask: net.CIDRMask(24, 32)},
+				&net.IPNet{IP: net.ParseIP("2001:db8::5"), Mask: net.CIDRMask(64, 128)},
+				&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+			}, nil
+		}
+		families := gatherFromCollector(t, c)
+
+		for _, metric := range families["node_network_receive_bytes_total"].Metric {
+			assert.Len(t, metric.Label, 1, "counters are keyed by device only")
+		}
+
+		info := families["node_network_address_info"]
+		require.NotNil(t, info)
+		assert.Len(t, info.Metric, 4)
+		assert.Equal(t, float64(1), metricValue(t, info, map[string]string{"device": "eth0", "address": "10.0.0.6", "family": "inet", "scope": "global"}))
+		assert.Equal(t, float64(1), metricValue(t, info, map[string]string{"address": "2001:db8::5", "family": "inet6", "scope": "global"}))
+		assert.Equal(t, float64(1), metricValue(t, info, map[string]string{"address": "fe80::1", "family": "inet6", "scope": "link"}))
+	})
+
+	t.Run("address info outside the default paths", func(t *testing.T) {
+		cfg := collectorConfig("netdev")
+		require.True(t, cfg.Entries["netdev"].Options.(*networkOptions).AddressInfo)
+
+		sc, err := NewSystemCollector(cfg, Paths{ProcPath: filepath.Join(root, "proc"), SysPath: filepath.Join(root, "sys"), RootPath: "/"}, logger)
+		require.NoError(t, err)
+		byName := collectByName(t, sc)
+
+		assert.Contains(t, byName, "node_network_receive_bytes_total")
+		assert.NotContains(t, byName, "node_network_address_info", "addresses of the agent's namespace must not be reported for host interfaces")
+	})
+}
+
+// fixedMountsCollector feeds a fixed set of mounts through filesystemCollector.emitMount
+type fixedMountsCollector struct {
+	*filesystemCollector
+	mounts []*procfs.MountInfo
+}
+
+func (c *fixedMountsCollector) collect(ch chan<- prometheus.Metric) error {
+	for _, mount := range c.mounts {
+		c.emitMount(ch, mount, mount.Source, mount.FSType, mount.MountPoint, "", "")
+	}
+	return nil
+}
+
+func TestFilesystemCollector(t *testing.T) {
+	mountPoint := t.TempDir()
+	missing := filepath.Join(mountPoint, "missing")
+
+	c := &fixedMountsCollector{
+		filesystemCollector: newFilesystemCollector("", "/", nil, true, time.Second, zaptest.NewLogger(t)),
+		mounts: []*procfs.MountInfo{
+			{Source: "/dev/vda1", FSType: "ext4", MountPoint: mountPoint, Options: map[string]string{"ro": ""}},
+			{Source: "/dev/vdb1", FSType: "ext4", MountPoint: missing, Options: map[string]string{"rw": ""}},
+		},
+	}
+	families := gatherFromCollector(t, c)
+
+	healthy := map[string]string{"mountpoint": mountPoint}
+	assert.Equal(t, 1.0, metricValue(t, families["node_filesystem_readonly"], healthy))
+	assert.Equal(t, 0.0, metricValue(t, families["node_filesystem_device_error"], healthy))
+	assert.Greater(t, metricValue(t, families["node_filesystem_files"], healthy), 0.0)
+	assert.Greater(t, metricValue(t, families["node_filesystem_size_bytes"], healthy), 0.0)
+
+	failed := map[string]string{"mountpoint": missing}
+	assert.Equal(t, 0.0, metricValue(t, families["node_filesystem_readonly"], failed))
+	assert.Equal(t, 1.0, metricValue(t, families["node_filesystem_device_error"], failed))
+	assert.Len(t, families["node_filesystem_size_bytes"].Metric, 1)
+}
+
+func TestFilesystemCollectorStuckMount(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+
+	fs := newFilesystemCollector("", "/", nil, true, 50*time.Millisecond, zaptest.NewLogger(t))
+	fs.statfs = func(path string, stat *syscall.Statfs_t) error {
+		calls.Add(1)
+		<-release
+		return syscall.Statfs(os.TempDir(), stat)
+	}
+	c := &fixedMountsCollector{
+		filesystemCollector: fs,
+		mounts:              []*procfs.MountInfo{{Source: "/dev/vdb1", FSType: "ext4", MountPoint: "/mnt/stuck"}},
+	}
+	labels := map[string]string{"mountpoint": "/mnt/stuck"}
+
+	// The hung call is abandoned after the timeout and reported as a device error
+	families := gatherFromCollector(t, c)
+	assert.Equal(t, 1.0, metricValue(t, families["node_filesystem_device_error"], labels))
+	assert.NotContains(t, families, "node_filesystem_size_bytes")
+
+	// While it is still pending the mountpoint is skipped without another statfs
+	families = gatherFromCollector(t, c)
+	assert.Equal(t, 1.0, metricValue(t, families["node_filesystem_device_error"], labels))
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Once the call returns the mountpoint is queried again
+	close(release)
+	assert.Eventually(t, func() bool {
+		fs.stuckMu.Lock()
+		defer fs.stuckMu.Unlock()
+		return !fs.stuckMounts["/mnt/stuck"]
+	}, time.Second, 10*time.Millisecond)
+
+	families = gatherFromCollector(t, c)
+	assert.Equal(t, 0.0, metricValue(t, families["node_filesystem_device_error"], labels))
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+// psiResources lists the resources exposed under /proc/pressure
+var psiResources = []string{"cpu", "memory", "io"}
+
+func init() {
+	// CPU, memory and I/O pressure stall information using procfs
+	registerCollector("pressure", true, newNoOptions, func(sc *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newPressureCollector(sc.procFS, logger)
+	})
+}
+
+type pressureCollector struct {
+	procFS procfs.FS
+	logger *zap.Logger
+	descs  map[string]*prometheus.Desc
+}
+
+// newPressureCollector creates a PSI collector, failing when the kernel does not
+// expose /proc/pressure (built without CONFIG_PSI or booted with psi=0)
+func newPressureCollector(procFS procfs.FS, logger *zap.Logger) (*pressureCollector, error) {
+	if _, err := procFS.PSIStatsForResource("cpu"); err != nil {
+		return nil, fmt.Errorf("pressure stall information not available: %w", err)
+	}
+
+	return &pressureCollector{procFS: procFS, logger: logger}, nil
+}
+
+func (c *pressureCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = make(map[string]*prometheus.Desc)
+	for _, resource := range psiResources {
+		c.descs[resource+"_waiting"] = prometheus.NewDesc("node_pressure_"+resource+"_waiting_seconds_total",
+			"Total time in seconds that some tasks were stalled waiting for "+resource+".", nil, nil)
+		c.descs[resource+"_stalled"] = prometheus.NewDesc("node_pressure_"+resource+"_stalled_seconds_total",
+			"Total time in seconds that all non-idle tasks were stalled waiting for "+resource+".", nil, nil)
+		c.descs[resource+"_waiting_ratio"] = prometheus.NewDesc("node_pressure_"+resource+"_waiting_ratio",
+			"Share of time some tasks were stalled waiting for "+resource+", averaged over the window.", []string{"window"}, nil)
+		c.descs[resource+"_stalled_ratio"] = prometheus.NewDesc("node_pressure_"+resource+"_stalled_ratio",
+			"Share of time all non-idle tasks were stalled waiting for "+resource+", averaged over the window.", []string{"window"}, nil)
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *pressureCollector) collect(ch chan<- prometheus.Metric) error {
+	var errs sourceErrors
+
+	for _, resource := range psiResources {
+		stats, err := c.procFS.PSIStatsForResource(resource)
+		if err != nil {
+			c.logger.Debug("Failed to get pressure stall information",
+				zap.String("resource", resource),
+				zap.Error(err))
+			errs.add(fmt.Errorf("failed to get %s pressure: %w", resource, err))
+			continue
+		}
+		errs.add(nil)
+
+		// The "full" line is missing for cpu on kernels before 5.13
+		c.emitLine(ch, stats.Some, resource+"_waiting")
+		c.emitLine(ch, stats.Full, resource+"_stalled")
+	}
+
+	return errs.err()
+}
+
+// emitLine sends the total stall time, reported in microseconds, and the
+// average percentages converted to ratios
+func (c *pressureCollector) emitLine(ch chan<- prometheus.Metric, line *procfs.PSILine, key string) {
+	if line == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.descs[key], prometheus.CounterValue, float64(line.Total)/1e6)
+	ch <- prometheus.MustNewConstMetric(c.descs[key+"_ratio"], prometheus.GaugeValue, line.Avg10/100, "10s")
+	ch <- prometheus.MustNewConstMetric(c.descs[key+"_ratio"], prometheus.GaugeValue, line.Avg60/100, "60s")
+	ch <- prometheus.MustNewConstMetric(c.descs[key+"_ratio"], prometheus.GaugeValue, line.Avg300/100, "300s")
+}
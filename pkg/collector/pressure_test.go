@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPressureCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "pressure/cpu", "some avg10=1.50 avg60=2.00 avg300=0.50 total=12500000\n")
+	writeFixture(t, root, "pressure/memory", "some avg10=0.00 avg60=0.10 avg300=0.20 total=300000\nfull avg10=0.00 avg60=0.05 avg300=0.10 total=150000\n")
+	writeFixture(t, root, "pressure/io", "some avg10=10.00 avg60=5.00 avg300=1.00 total=9000000\nfull avg10=8.00 avg60=4.00 avg300=0.80 total=7000000\n")
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+
+	c, err := newPressureCollector(procFS, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	families := gatherFromCollector(t, c)
+
+	assert.Equal(t, 12.5, metricValue(t, families["node_pressure_cpu_waiting_seconds_total"], nil))
+	assert.Equal(t, 0.015, metricValue(t, families["node_pressure_cpu_waiting_ratio"], map[string]string{"window": "10s"}))
+	assert.NotContains(t, families, "node_pressure_cpu_stalled_seconds_total", "older kernels have no cpu full line")
+	assert.Equal(t, 0.15, metricValue(t, families["node_pressure_memory_stalled_seconds_total"], nil))
+	assert.Equal(t, float64(9), metricValue(t, families["node_pressure_io_waiting_seconds_total"], nil))
+	assert.Equal(t, float64(7), metricValue(t, families["node_pressure_io_stalled_seconds_total"], nil))
+	assert.InDelta(t, 0.008, metricValue(t, families["node_pressure_io_stalled_ratio"], map[string]string{"window": "300s"}), 1e-9)
+}
+
+func TestPressureCollectorFailure(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "pressure/cpu", "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+	c, err := newPressureCollector(procFS, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	_, err = gatherWithError(t, c)
+	assert.NoError(t, err, "the resources that can be read are reported")
+
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "pressure")))
+	_, err = gatherWithError(t, c)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get cpu pressure")
+}
+
+func TestPressureCollectorUnavailable(t *testing.T) {
+	procFS, err := procfs.NewFS(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = newPressureCollector(procFS, zaptest.NewLogger(t))
+	assert.Error(t, err)
+}
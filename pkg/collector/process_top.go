@@ -0,0 +1,305 @@
+package collector
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultProcessTopN is used when the configured top-N is not positive
+	defaultProcessTopN = 10
+
+	// maxReportedProcesses caps the number of processes reported per collection,
+	// including allow-listed ones, so busy hosts cannot blow up the payload
+	maxReportedProcesses = 100
+
+	// maxCmdlineLabelLength truncates the cmdline label to keep label values small
+	maxCmdlineLabelLength = 128
+)
+
+// processTopSortKeys lists the supported ranking keys
+var processTopSortKeys = []string{"cpu", "memory", "fds", "io"}
+
+// processSample holds the per-process values gathered during one collection
+type processSample struct {
+	pid        int
+	comm       string
+	cpuSeconds float64
+	rssBytes   float64
+	fds        int
+	hasFDs     bool
+	readBytes  uint64
+	writeBytes uint64
+	hasIO      bool
+	score      float64
+}
+
+// processTopOptions configures the process_top collector
+type processTopOptions struct {
+	TopN int `yaml:"top_n" json:"top_n"`
+	// SortBy is one of processTopSortKeys
+	SortBy string `yaml:"sort_by" json:"sort_by"`
+	// AllowList names processes (comm) that are always reported
+	AllowList []string `yaml:"allow_list" json:"allow_list"`
+}
+
+func newProcessTopOptions() config.CollectorOptions {
+	return &processTopOptions{TopN: defaultProcessTopN, SortBy: "cpu"}
+}
+
+func (o *processTopOptions) Validate() error {
+	if o.TopN <= 0 {
+		return fmt.Errorf("top_n must be positive")
+	}
+	if o.TopN > maxReportedProcesses {
+		return fmt.Errorf("top_n must not exceed %d", maxReportedProcesses)
+	}
+	if !slices.Contains(processTopSortKeys, o.SortBy) {
+		return fmt.Errorf("invalid sort_by: %s (must be one of %s)", o.SortBy, strings.Join(processTopSortKeys, ", "))
+	}
+	return nil
+}
+
+func init() {
+	// Per-process metrics for the top resource consumers using procfs. It is
+	// opt-in because its cmdline label may carry secrets and its pid label
+	// creates new series as processes come and go
+	registerCollector("process_top", false, newProcessTopOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		opts := options.(*processTopOptions)
+		return newProcessTopCollector(sc.procFS, opts.TopN, opts.SortBy, opts.AllowList, logger), nil
+	})
+}
+
+type processTopCollector struct {
+	procFS    procfs.FS
+	logger    *zap.Logger
+	topN      int
+	sortBy    string
+	allowList map[string]bool
+	descs     map[string]*prometheus.Desc
+
+	// Previous cumulative values by PID, used to rank by recent CPU and I/O
+	// usage rather than by lifetime totals
+	mu      sync.Mutex
+	lastCPU map[int]float64
+	lastIO  map[int]uint64
+}
+
+// newProcessTopCollector creates a top-N process collector, falling back to
+// ranking by CPU when no sort key is given
+func newProcessTopCollector(procFS procfs.FS, topN int, sortBy string, allowList []string, logger *zap.Logger) *processTopCollector {
+	if topN <= 0 {
+		topN = defaultProcessTopN
+	}
+	if sortBy == "" {
+		sortBy = "cpu"
+	}
+
+	allowed := make(map[string]bool, len(allowList))
+	for _, name := range allowList {
+		allowed[name] = true
+	}
+
+	return &processTopCollector{
+		procFS:    procFS,
+		logger:    logger,
+		topN:      topN,
+		sortBy:    sortBy,
+		allowList: allowed,
+		lastCPU:   make(map[int]float64),
+		lastIO:    make(map[int]uint64),
+	}
+}
+
+func (c *processTopCollector) Describe(ch chan<- *prometheus.Desc) {
+	labels := []string{"pid", "comm", "cmdline"}
+	c.descs = map[string]*prometheus.Desc{
+		"cpu":         prometheus.NewDesc("node_process_cpu_seconds_total", "Total user and system CPU time spent by the process in seconds.", labels, nil),
+		"rss":         prometheus.NewDesc("node_process_resident_memory_bytes", "Resident memory size of the process in bytes.", labels, nil),
+		"fds":         prometheus.NewDesc("node_process_open_fds", "Number of open file descriptors of the process.", labels, nil),
+		"read_bytes":  prometheus.NewDesc("node_process_read_bytes_total", "Number of bytes the process caused to be read from storage.", labels, nil),
+		"write_bytes": prometheus.NewDesc("node_process_written_bytes_total", "Number of bytes the process caused to be written to storage.", labels, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *processTopCollector) collect(ch chan<- prometheus.Metric) error {
+	procs, err := c.procFS.AllProcs()
+	if err != nil {
+		return fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	currentCPU := make(map[int]float64, len(procs))
+	currentIO := make(map[int]uint64, len(procs))
+	samples := make([]*processSample, 0, len(procs))
+
+	for _, proc := range procs {
+		stat, err := proc.Stat()
+		if err != nil {
+			// Processes routinely exit between listing /proc and reading their stat file
+			continue
+		}
+
+		sample := &processSample{
+			pid:        proc.PID,
+			comm:       stat.Comm,
+			cpuSeconds: stat.CPUTime(),
+			rssBytes:   float64(stat.ResidentMemory()),
+		}
+		currentCPU[proc.PID] = sample.cpuSeconds
+
+		// Only read the more expensive files for every process when ranking needs them
+		switch c.sortBy {
+		case "io":
+			c.readIO(proc, sample)
+			if sample.hasIO {
+				currentIO[proc.PID] = sample.readBytes + sample.writeBytes
+			}
+		case "fds":
+			c.readFDs(proc, sample)
+		}
+
+		sample.score = c.score(sample)
+		samples = append(samples, sample)
+	}
+
+	c.lastCPU = currentCPU
+	if c.sortBy == "io" {
+		c.lastIO = currentIO
+	}
+
+	for _, sample := range c.selectProcesses(samples) {
+		c.emit(ch, sample)
+	}
+	return nil
+}
+
+// score returns the ranking value of a sample for the configured sort key
+func (c *processTopCollector) score(sample *processSample) float64 {
+	switch c.sortBy {
+	case "memory":
+		return sample.rssBytes
+	case "fds":
+		return float64(sample.fds)
+	case "io":
+		if !sample.hasIO {
+			return 0
+		}
+		return float64(sample.readBytes+sample.writeBytes) - float64(c.lastIO[sample.pid])
+	default:
+		return sample.cpuSeconds - c.lastCPU[sample.pid]
+	}
+}
+
+// selectProcesses returns the top-N samples by score plus any allow-listed
+// processes, capped at maxReportedProcesses
+func (c *processTopCollector) selectProcesses(samples []*processSample) []*processSample {
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].score > samples[j].score
+	})
+
+	selected := make([]*processSample, 0, c.topN)
+	for i, sample := range samples {
+		if len(selected) >= maxReportedProcesses {
+			c.logger.Debug("Process report limit reached, dropping remaining processes",
+				zap.Int("limit", maxReportedProcesses))
+			break
+		}
+
+		if i < c.topN || c.allowList[sample.comm] {
+			selected = append(selected, sample)
+		}
+	}
+
+	return selected
+}
+
+// emit reads the remaining per-process files for a selected sample and sends its metrics
+func (c *processTopCollector) emit(ch chan<- prometheus.Metric, sample *processSample) {
+	proc, err := c.procFS.Proc(sample.pid)
+	if err != nil {
+		return
+	}
+
+	if status, err := proc.NewStatus(); err == nil {
+		sample.rssBytes = float64(status.VmRSS)
+	}
+	if !sample.hasIO {
+		c.readIO(proc, sample)
+	}
+	if !sample.hasFDs {
+		c.readFDs(proc, sample)
+	}
+
+	cmdline := ""
+	if args, err := proc.CmdLine(); err == nil {
+		cmdline = processCmdlineLabel(args)
+	}
+
+	labels := []string{strconv.Itoa(sample.pid), strings.ToValidUTF8(sample.comm, "?"), cmdline}
+
+	ch <- prometheus.MustNewConstMetric(c.descs["cpu"], prometheus.CounterValue, sample.cpuSeconds, labels...)
+	ch <- prometheus.MustNewConstMetric(c.descs["rss"], prometheus.GaugeValue, sample.rssBytes, labels...)
+	if sample.hasFDs {
+		ch <- prometheus.MustNewConstMetric(c.descs["fds"], prometheus.GaugeValue, float64(sample.fds), labels...)
+	}
+	if sample.hasIO {
+		ch <- prometheus.MustNewConstMetric(c.descs["read_bytes"], prometheus.CounterValue, float64(sample.readBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(c.descs["write_bytes"], prometheus.CounterValue, float64(sample.writeBytes), labels...)
+	}
+}
+
+// readIO fills the I/O counters of a sample; they are unreadable without
+// sufficient privileges, in which case the sample is left without I/O data
+func (c *processTopCollector) readIO(proc procfs.Proc, sample *processSample) {
+	io, err := proc.IO()
+	if err != nil {
+		return
+	}
+
+	sample.readBytes = io.ReadBytes
+	sample.writeBytes = io.WriteBytes
+	sample.hasIO = true
+}
+
+// readFDs fills the open file descriptor count of a sample
+func (c *processTopCollector) readFDs(proc procfs.Proc, sample *processSample) {
+	fds, err := proc.FileDescriptorsLen()
+	if err != nil {
+		return
+	}
+
+	sample.fds = fds
+	sample.hasFDs = true
+}
+
+// processCmdlineLabel joins a process command line into a bounded label value
+func processCmdlineLabel(args []string) string {
+	if len(args) > 0 {
+		args[0] = filepath.Base(args[0])
+	}
+
+	cmdline := strings.Join(args, " ")
+	if len(cmdline) > maxCmdlineLabelLength {
+		cmdline = cmdline[:maxCmdlineLabelLength]
+	}
+
+	// Label values must be valid UTF-8, which neither arguments nor truncation guarantee
+	return strings.ToValidUTF8(cmdline, "?")
+}
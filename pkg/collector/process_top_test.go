@@ -0,0 +1,73 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// procPIDStatWithTimes renders a /proc/<pid>/stat line with the given user and system ticks
+func procPIDStatWithTimes(pid int, comm string, utime, stime int) string {
+	return fmt.Sprintf("%d (%s) S 1 %d %d 0 -1 4194560 100 0 0 0 %d %d 0 0 20 0 1 0 100 1000000 200 18446744073709551615 0 0 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0\n",
+		pid, comm, pid, pid, utime, stime)
+}
+
+func TestProcessTopCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "stat", "cpu  100 0 50 1000 10 0 5 0 0 0\nbtime 1700000000\nprocesses 10\n")
+	writeFixture(t, root, "10/stat", procPIDStatWithTimes(10, "java", 5000, 1000))
+	writeFixture(t, root, "10/cmdline", "/usr/bin/java\x00-jar\x00app.jar\x00")
+	writeFixture(t, root, "10/io", "rchar: 1\nwchar: 1\nsyscr: 1\nsyscw: 1\nread_bytes: 4096\nwrite_bytes: 8192\ncancelled_write_bytes: 0\n")
+	writeFixture(t, root, "20/stat", procPIDStatWithTimes(20, "postgres", 3000, 0))
+	writeFixture(t, root, "30/stat", procPIDStatWithTimes(30, "sshd", 1, 1))
+	writeFixture(t, root, "40/stat", procPIDStatWithTimes(40, "bash", 10, 0))
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+
+	c := newProcessTopCollector(procFS, 2, "cpu", []string{"sshd"}, zaptest.NewLogger(t))
+	families := gatherFromCollector(t, c)
+
+	cpuFamily := families["node_process_cpu_seconds_total"]
+	require.NotNil(t, cpuFamily)
+	assert.Len(t, cpuFamily.Metric, 3, "top 2 plus the allow-listed process")
+
+	assert.Equal(t, float64(60), metricValue(t, cpuFamily, map[string]string{"pid": "10", "comm": "java", "cmdline": "java -jar app.jar"}))
+	assert.Equal(t, float64(30), metricValue(t, cpuFamily, map[string]string{"pid": "20", "comm": "postgres"}))
+	assert.Equal(t, 0.02, metricValue(t, cpuFamily, map[string]string{"pid": "30", "comm": "sshd"}))
+
+	assert.Equal(t, float64(4096), metricValue(t, families["node_process_read_bytes_total"], map[string]string{"pid": "10"}))
+	assert.Equal(t, float64(8192), metricValue(t, families["node_process_written_bytes_total"], map[string]string{"pid": "10"}))
+	assert.Len(t, families["node_process_read_bytes_total"].Metric, 1, "processes without readable io are not reported")
+}
+
+func TestProcessTopCollectorRanksByRecentCPU(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "stat", "cpu  100 0 50 1000 10 0 5 0 0 0\nbtime 1700000000\nprocesses 10\n")
+	writeFixture(t, root, "10/stat", procPIDStatWithTimes(10, "java", 5000, 0))
+	writeFixture(t, root, "20/stat", procPIDStatWithTimes(20, "postgres", 100, 0))
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+
+	c := newProcessTopCollector(procFS, 1, "cpu", nil, zaptest.NewLogger(t))
+	families := gatherFromCollector(t, c)
+	assert.Equal(t, float64(50), metricValue(t, families["node_process_cpu_seconds_total"], map[string]string{"comm": "java"}))
+
+	// java is idle while postgres burns CPU, so postgres should now rank first
+	writeFixture(t, root, "20/stat", procPIDStatWithTimes(20, "postgres", 600, 0))
+	families = gatherFromCollector(t, c)
+	require.Len(t, families["node_process_cpu_seconds_total"].Metric, 1)
+	assert.Equal(t, "postgres", labelValue(families["node_process_cpu_seconds_total"].Metric[0], "comm"))
+}
+
+func TestProcessCmdlineLabel(t *testing.T) {
+	assert.Equal(t, "nginx -g daemon off;", processCmdlineLabel([]string{"/usr/sbin/nginx", "-g", "daemon off;"}))
+	assert.Equal(t, "", processCmdlineLabel(nil))
+	assert.Len(t, processCmdlineLabel([]string{strings.Repeat("a", 500)}), maxCmdlineLabelLength)
+}
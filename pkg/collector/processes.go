@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+// processStates lists the process states that are always reported, even when
+// no process is currently in them, so that zombie and D-state alerts see zeros
+// instead of missing series.
+var processStates = []string{"R", "S", "D", "Z", "T", "I"}
+
+func init() {
+	// Process count and limit metrics using procfs
+	registerCollector("processes", true, newNoOptions, func(sc *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return &processesCollector{procFS: sc.procFS, procPath: sc.procPath, logger: logger}, nil
+	})
+}
+
+type processesCollector struct {
+	procFS   procfs.FS
+	procPath string
+	logger   *zap.Logger
+	descs    map[string]*prometheus.Desc
+}
+
+func (c *processesCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = map[string]*prometheus.Desc{
+		"state":         prometheus.NewDesc("node_processes_state", "Number of processes in each state.", []string{"state"}, nil),
+		"pids":          prometheus.NewDesc("node_processes_pids", "Number of PIDs.", nil, nil),
+		"threads":       prometheus.NewDesc("node_processes_threads", "Allocated threads in system.", nil, nil),
+		"forks":         prometheus.NewDesc("node_forks_total", "Total number of forks.", nil, nil),
+		"max_processes": prometheus.NewDesc("node_processes_max_processes", "Number of max PIDs limit.", nil, nil),
+		"max_threads":   prometheus.NewDesc("node_processes_max_threads", "Limit of threads in the system.", nil, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *processesCollector) collect(ch chan<- prometheus.Metric) error {
+	procs, err := c.procFS.AllProcs()
+	if err != nil {
+		return fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	states := make(map[string]int)
+	for _, state := range processStates {
+		states[state] = 0
+	}
+
+	pids := 0
+	threads := 0
+	for _, proc := range procs {
+		stat, err := proc.Stat()
+		if err != nil {
+			// Processes routinely exit between listing /proc and reading their stat file
+			continue
+		}
+
+		pids++
+		threads += stat.NumThreads
+		states[stat.State]++
+	}
+
+	for state, count := range states {
+		ch <- prometheus.MustNewConstMetric(c.descs["state"], prometheus.GaugeValue, float64(count), state)
+	}
+	ch <- prometheus.MustNewConstMetric(c.descs["pids"], prometheus.GaugeValue, float64(pids))
+	ch <- prometheus.MustNewConstMetric(c.descs["threads"], prometheus.GaugeValue, float64(threads))
+
+	stat, err := c.procFS.Stat()
+	if err != nil {
+		c.logger.Debug("Failed to get fork count", zap.Error(err))
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.descs["forks"], prometheus.CounterValue, float64(stat.ProcessCreated))
+	}
+
+	pidMax, err := readUintFromFile(filepath.Join(c.procPath, "sys", "kernel", "pid_max"))
+	if err != nil {
+		c.logger.Debug("Failed to get pid_max", zap.Error(err))
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.descs["max_processes"], prometheus.GaugeValue, float64(pidMax))
+	}
+
+	threadsMax, err := readUintFromFile(filepath.Join(c.procPath, "sys", "kernel", "threads-max"))
+	if err != nil {
+		c.logger.Debug("Failed to get threads-max", zap.Error(err))
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.descs["max_threads"], prometheus.GaugeValue, float64(threadsMax))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// procPIDStat renders a minimal /proc/<pid>/stat line with the given state and thread count
+func procPIDStat(pid int, comm, state string, threads int) string {
+	return fmt.Sprintf("%d (%s) %s 1 %d %d 0 -1 4194560 100 0 0 0 10 5 0 0 20 0 %d 0 100 1000000 200 18446744073709551615 0 0 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0\n",
+		pid, comm, state, pid, pid, threads)
+}
+
+func TestProcessesCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "stat", "cpu  100 0 50 1000 10 0 5 0 0 0\ncpu0 100 0 50 1000 10 0 5 0 0 0\nintr 0\nctxt 1000\nbtime 1700000000\nprocesses 4242\nprocs_running 1\nprocs_blocked 1\n")
+	writeFixture(t, root, "sys/kernel/pid_max", "4194304\n")
+	writeFixture(t, root, "sys/kernel/threads-max", "63448\n")
+	writeFixture(t, root, "1/stat", procPIDStat(1, "systemd", "S", 1))
+	writeFixture(t, root, "100/stat", procPIDStat(100, "postgres", "R", 4))
+	writeFixture(t, root, "200/stat", procPIDStat(200, "defunct worker", "Z", 1))
+	writeFixture(t, root, "300/stat", procPIDStat(300, "jbd2/vda1-8", "D", 1))
+	writeFixture(t, root, "301/stat", procPIDStat(301, "kworker", "D", 1))
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+
+	c := &processesCollector{procFS: procFS, procPath: root, logger: zaptest.NewLogger(t)}
+	families := gatherFromCollector(t, c)
+
+	assert.Equal(t, float64(1), metricValue(t, families["node_processes_state"], map[string]string{"state": "R"}))
+	assert.Equal(t, float64(1), metricValue(t, families["node_processes_state"], map[string]string{"state": "S"}))
+	assert.Equal(t, float64(1), metricValue(t, families["node_processes_state"], map[string]string{"state": "Z"}))
+	assert.Equal(t, float64(2), metricValue(t, families["node_processes_state"], map[string]string{"state": "D"}))
+	assert.Equal(t, float64(0), metricValue(t, families["node_processes_state"], map[string]string{"state": "T"}))
+	assert.Equal(t, float64(5), metricValue(t, families["node_processes_pids"], nil))
+	assert.Equal(t, float64(8), metricValue(t, families["node_processes_threads"], nil))
+	assert.Equal(t, float64(4242), metricValue(t, families["node_forks_total"], nil))
+	assert.Equal(t, float64(4194304), metricValue(t, families["node_processes_max_processes"], nil))
+	assert.Equal(t, float64(63448), metricValue(t, families["node_processes_max_threads"], nil))
+}
@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+)
+
+// collectorFactory creates a collector from the host paths of sc and its
+// options, which have the type registered with it. A factory error wrapping
+// errCollectorUnavailable skips the collector without a warning. The collector
+// logs through logger, and its collect returns an error when it could not
+// produce its metrics, which marks the collection as failed
+type collectorFactory func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error)
+
+// factories holds the factory of every registered collector by name
+var factories = make(map[string]collectorFactory)
+
+// registerCollector makes a collector available under name, which is also its
+// key in the configuration file. enabled sets whether it runs by default and
+// newOptions returns its options with their defaults. Collectors register
+// from init functions
+func registerCollector(name string, enabled bool, newOptions func() config.CollectorOptions, factory collectorFactory) {
+	config.RegisterCollector(name, enabled, newOptions)
+	factories[name] = factory
+}
+
+// noOptions is the options type of collectors without options
+type noOptions struct{}
+
+func newNoOptions() config.CollectorOptions {
+	return &noOptions{}
+}
+
+func (o *noOptions) Validate() error {
+	return nil
+}
@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+)
+
+func TestRegisteredCollectors(t *testing.T) {
+	names := config.RegisteredCollectors()
+	assert.Len(t, names, 22)
+	assert.Len(t, factories, len(names))
+
+	cfg := config.NewCollectorConfig()
+	for _, name := range names {
+		assert.Contains(t, factories, name)
+		if name == "process_top" {
+			assert.False(t, cfg.Enabled(name), "process_top should be opt-in")
+		} else {
+			assert.True(t, cfg.Enabled(name), "collector %s should be enabled by default", name)
+		}
+		assert.NoError(t, cfg.Entries[name].Options.Validate(), "default options of %s should be valid", name)
+	}
+}
+
+func TestCollectorOptionsValidate(t *testing.T) {
+	tests := []struct {
+		options config.CollectorOptions
+		err     string
+	}{
+		{&processTopOptions{TopN: 0, SortBy: "cpu"}, "top_n must be positive"},
+		{&processTopOptions{TopN: maxReportedProcesses + 1, SortBy: "cpu"}, "top_n must not exceed 100"},
+		{&processTopOptions{TopN: 10, SortBy: "threads"}, "invalid sort_by: threads"},
+		{&memoryOptions{Exclude: "Hugepages("}, "invalid exclude"},
+		{&vmstatOptions{Fields: "^(pgpg"}, "invalid fields"},
+		{&diskStatsOptions{DeviceInclude: "^(dm-"}, "invalid device_include"},
+		{&filesystemOptions{StatfsTimeout: 0}, "statfs_timeout must be positive"},
+		{&networkOptions{DeviceExclude: "^(veth"}, "invalid device_exclude"},
+		{&netstatOptions{Fields: "^(Tcp_"}, "invalid fields"},
+		{&tcpStatOptions{Ports: []int{443, 70000}}, "invalid ports entry: 70000"},
+	}
+
+	for _, tt := range tests {
+		err := tt.options.Validate()
+		require.Error(t, err, "%#v", tt.options)
+		assert.Contains(t, err.Error(), tt.err)
+	}
+
+	assert.NoError(t, (&filesystemOptions{StatfsTimeout: time.Second}).Validate())
+	assert.NoError(t, (&tcpStatOptions{Ports: []int{22, 443}}).Validate())
+}
@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// Per-CPU scheduler run and wait times using procfs
+	registerCollector("schedstat", true, newNoOptions, func(sc *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newSchedstatCollector(sc.procFS, sc.procPath, logger)
+	})
+}
+
+type schedstatCollector struct {
+	procFS procfs.FS
+	logger *zap.Logger
+	descs  map[string]*prometheus.Desc
+}
+
+// newSchedstatCollector creates a scheduler statistics collector, returning
+// errCollectorUnavailable when the kernel is built without CONFIG_SCHEDSTATS
+func newSchedstatCollector(procFS procfs.FS, procPath string, logger *zap.Logger) (*schedstatCollector, error) {
+	if _, err := os.Stat(filepath.Join(procPath, "schedstat")); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s/schedstat does not exist", errCollectorUnavailable, procPath)
+	}
+
+	return &schedstatCollector{procFS: procFS, logger: logger}, nil
+}
+
+func (c *schedstatCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = map[string]*prometheus.Desc{
+		"running":    prometheus.NewDesc("node_schedstat_running_seconds_total", "Number of seconds CPU spent running a process.", []string{"cpu"}, nil),
+		"waiting":    prometheus.NewDesc("node_schedstat_waiting_seconds_total", "Number of seconds spent by processes waiting for this CPU.", []string{"cpu"}, nil),
+		"timeslices": prometheus.NewDesc("node_schedstat_timeslices_total", "Number of timeslices executed by CPU.", []string{"cpu"}, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *schedstatCollector) collect(ch chan<- prometheus.Metric) error {
+	stats, err := c.procFS.Schedstat()
+	if err != nil {
+		return fmt.Errorf("failed to get scheduler stats: %w", err)
+	}
+
+	// Running and waiting times are reported in nanoseconds
+	for _, cpu := range stats.CPUs {
+		ch <- prometheus.MustNewConstMetric(c.descs["running"], prometheus.CounterValue, float64(cpu.RunningNanoseconds)/1e9, cpu.CPUNum)
+		ch <- prometheus.MustNewConstMetric(c.descs["waiting"], prometheus.CounterValue, float64(cpu.WaitingNanoseconds)/1e9, cpu.CPUNum)
+		ch <- prometheus.MustNewConstMetric(c.descs["timeslices"], prometheus.CounterValue, float64(cpu.RunTimeslices), cpu.CPUNum)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSchedstatCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "schedstat", `version 15
+timestamp 15819019232
+cpu0 498494191 0 3533438552 2553969831 3853684107 2465731542 2045936778163039 343796328169361 4767485306
+domain0 00000000,00000003 212499247 210112015 1861015 1860405436 536440 369895 32599 210079416 25368550 24241256 384652 927363878 807233 6366 1647 0
+cpu1 518377256 0 4155211005 2778589869 10466382 2867629021 1904686152592476 364107263788241 5145567945
+`)
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+
+	c, err := newSchedstatCollector(procFS, root, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	families := gatherFromCollector(t, c)
+
+	assert.Len(t, families["node_schedstat_running_seconds_total"].Metric, 2)
+	assert.Equal(t, 2045936.778163039, metricValue(t, families["node_schedstat_running_seconds_total"], map[string]string{"cpu": "0"}))
+	assert.Equal(t, 364107.263788241, metricValue(t, families["node_schedstat_waiting_seconds_total"], map[string]string{"cpu": "1"}))
+	assert.Equal(t, float64(4767485306), metricValue(t, families["node_schedstat_timeslices_total"], map[string]string{"cpu": "0"}))
+}
+
+func TestSchedstatCollectorUnavailable(t *testing.T) {
+	root := t.TempDir()
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+
+	_, err = newSchedstatCollector(procFS, root, zaptest.NewLogger(t))
+	assert.True(t, errors.Is(err, errCollectorUnavailable))
+}
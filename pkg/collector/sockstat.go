@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// Socket usage and TCP memory metrics using procfs
+	registerCollector("sockstat", true, newNoOptions, func(sc *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newSockstatCollector(sc.procFS, logger), nil
+	})
+}
+
+type sockstatCollector struct {
+	procFS   procfs.FS
+	pageSize int
+	logger   *zap.Logger
+}
+
+func newSockstatCollector(procFS procfs.FS, logger *zap.Logger) *sockstatCollector {
+	return &sockstatCollector{procFS: procFS, pageSize: os.Getpagesize(), logger: logger}
+}
+
+// Describe sends no descriptors: the protocols listed in sockstat depend on the
+// running kernel and loaded modules, so the collector is registered as unchecked
+func (c *sockstatCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func (c *sockstatCollector) collect(ch chan<- prometheus.Metric) error {
+	stat, err := c.procFS.NetSockstat()
+	if err != nil {
+		return fmt.Errorf("failed to get socket stats: %w", err)
+	}
+	c.emit(ch, stat)
+
+	// sockstat6 is absent when IPv6 is disabled
+	stat6, err := c.procFS.NetSockstat6()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			c.logger.Debug("Failed to get IPv6 socket stats", zap.Error(err))
+		}
+		return nil
+	}
+	c.emit(ch, stat6)
+	return nil
+}
+
+// emit sends the socket counts of one sockstat file; TCP and UDP memory is
+// reported by the kernel in pages and additionally emitted in bytes
+func (c *sockstatCollector) emit(ch chan<- prometheus.Metric, stat *procfs.NetSockstat) {
+	if stat.Used != nil {
+		c.gauge(ch, "sockets_used", "Number of IPv4 sockets in use.", float64(*stat.Used))
+	}
+
+	for _, protocol := range stat.Protocols {
+		prefix := protocol.Protocol + "_"
+		c.gauge(ch, prefix+"inuse", "Number of "+protocol.Protocol+" sockets in use.", float64(protocol.InUse))
+
+		if protocol.Orphan != nil {
+			c.gauge(ch, prefix+"orphan", "Number of orphaned "+protocol.Protocol+" sockets.", float64(*protocol.Orphan))
+		}
+		if protocol.TW != nil {
+			c.gauge(ch, prefix+"tw", "Number of "+protocol.Protocol+" sockets in TIME_WAIT.", float64(*protocol.TW))
+		}
+		if protocol.Alloc != nil {
+			c.gauge(ch, prefix+"alloc", "Number of allocated "+protocol.Protocol+" sockets.", float64(*protocol.Alloc))
+		}
+		if protocol.Mem != nil {
+			c.gauge(ch, prefix+"mem", "Memory used by "+protocol.Protocol+" sockets in pages.", float64(*protocol.Mem))
+			c.gauge(ch, prefix+"mem_bytes", "Memory used by "+protocol.Protocol+" sockets in bytes.", float64(*protocol.Mem*c.pageSize))
+		}
+		if protocol.Memory != nil {
+			c.gauge(ch, prefix+"memory", "Memory used by "+protocol.Protocol+" in bytes.", float64(*protocol.Memory))
+		}
+	}
+}
+
+func (c *sockstatCollector) gauge(ch chan<- prometheus.Metric, name, help string, value float64) {
+	desc := prometheus.NewDesc("node_sockstat_"+name, help, nil, nil)
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value)
+}
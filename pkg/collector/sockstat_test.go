@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSockstatCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "net/sockstat", `sockets: used 1200
+TCP: inuse 40 orphan 3 tw 250 alloc 60 mem 25
+UDP: inuse 8 mem 4
+UDPLITE: inuse 0
+RAW: inuse 1
+FRAG: inuse 0 memory 0
+`)
+	writeFixture(t, root, "net/sockstat6", `TCP6: inuse 12
+UDP6: inuse 3
+UDPLITE6: inuse 0
+RAW6: inuse 0
+FRAG6: inuse 0 memory 0
+`)
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+
+	c := newSockstatCollector(procFS, zaptest.NewLogger(t))
+	c.pageSize = 4096
+	families := gatherFromCollector(t, c)
+
+	assert.Equal(t, float64(1200), metricValue(t, families["node_sockstat_sockets_used"], nil))
+	assert.Equal(t, float64(40), metricValue(t, families["node_sockstat_TCP_inuse"], nil))
+	assert.Equal(t, float64(3), metricValue(t, families["node_sockstat_TCP_orphan"], nil))
+	assert.Equal(t, float64(250), metricValue(t, families["node_sockstat_TCP_tw"], nil))
+	assert.Equal(t, float64(60), metricValue(t, families["node_sockstat_TCP_alloc"], nil))
+	assert.Equal(t, float64(25), metricValue(t, families["node_sockstat_TCP_mem"], nil))
+	assert.Equal(t, float64(25*4096), metricValue(t, families["node_sockstat_TCP_mem_bytes"], nil))
+	assert.Equal(t, float64(4*4096), metricValue(t, families["node_sockstat_UDP_mem_bytes"], nil))
+	assert.Equal(t, float64(1), metricValue(t, families["node_sockstat_RAW_inuse"], nil))
+	assert.Equal(t, float64(0), metricValue(t, families["node_sockstat_FRAG_memory"], nil))
+	assert.Equal(t, float64(12), metricValue(t, families["node_sockstat_TCP6_inuse"], nil))
+	assert.Equal(t, float64(3), metricValue(t, families["node_sockstat_UDP6_inuse"], nil))
+}
+
+func TestSockstatCollectorWithoutIPv6(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "net/sockstat", "sockets: used 5\nTCP: inuse 1 orphan 0 tw 0 alloc 1 mem 1\n")
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+
+	families := gatherFromCollector(t, newSockstatCollector(procFS, zaptest.NewLogger(t)))
+	assert.Equal(t, float64(5), metricValue(t, families["node_sockstat_sockets_used"], nil))
+	assert.NotContains(t, families, "node_sockstat_TCP6_inuse")
+}
@@ -0,0 +1,121 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+// tcpStates maps the hexadecimal st column of /proc/net/tcp to state names
+var tcpStates = map[uint64]string{
+	0x01: "established",
+	0x02: "syn_sent",
+	0x03: "syn_recv",
+	0x04: "fin_wait1",
+	0x05: "fin_wait2",
+	0x06: "time_wait",
+	0x07: "close",
+	0x08: "close_wait",
+	0x09: "last_ack",
+	0x0A: "listen",
+	0x0B: "closing",
+}
+
+// tcpStatOptions configures the tcpstat collector
+type tcpStatOptions struct {
+	// Ports are local ports whose connections are also counted per port
+	Ports []int `yaml:"ports" json:"ports"`
+}
+
+func newTCPStatOptions() config.CollectorOptions {
+	return &tcpStatOptions{}
+}
+
+func (o *tcpStatOptions) Validate() error {
+	for _, port := range o.Ports {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("invalid ports entry: %d (must be between 1 and 65535)", port)
+		}
+	}
+	return nil
+}
+
+func init() {
+	// TCP connection state counts using procfs
+	registerCollector("tcpstat", true, newTCPStatOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newTCPStatCollector(sc.procFS, options.(*tcpStatOptions).Ports, logger), nil
+	})
+}
+
+type tcpStatCollector struct {
+	procFS procfs.FS
+	ports  []int
+	logger *zap.Logger
+	descs  map[string]*prometheus.Desc
+}
+
+// newTCPStatCollector creates a TCP connection state collector; connections
+// on the given local ports are additionally counted per port
+func newTCPStatCollector(procFS procfs.FS, ports []int, logger *zap.Logger) *tcpStatCollector {
+	return &tcpStatCollector{procFS: procFS, ports: ports, logger: logger}
+}
+
+func (c *tcpStatCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = map[string]*prometheus.Desc{
+		"states": prometheus.NewDesc("node_tcp_connection_states", "Number of IPv4 and IPv6 TCP connections by state.", []string{"state"}, nil),
+	}
+	if len(c.ports) > 0 {
+		c.descs["port_states"] = prometheus.NewDesc("node_tcp_port_connection_states", "Number of IPv4 and IPv6 TCP connections by local port and state.", []string{"port", "state"}, nil)
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *tcpStatCollector) collect(ch chan<- prometheus.Metric) error {
+	states := make(map[uint64]int)
+	portStates := make(map[int]map[uint64]int, len(c.ports))
+	for _, port := range c.ports {
+		portStates[port] = make(map[uint64]int)
+	}
+
+	tcp, err := c.procFS.NetTCP()
+	if err != nil {
+		return fmt.Errorf("failed to get TCP connections: %w", err)
+	}
+	c.count(tcp, states, portStates)
+
+	// tcp6 is absent when IPv6 is disabled
+	if tcp6, err := c.procFS.NetTCP6(); err == nil {
+		c.count(tcp6, states, portStates)
+	} else if !os.IsNotExist(err) {
+		c.logger.Debug("Failed to get IPv6 TCP connections", zap.Error(err))
+	}
+
+	// Every state is reported, including empty ones, so series stay continuous
+	for st, state := range tcpStates {
+		ch <- prometheus.MustNewConstMetric(c.descs["states"], prometheus.GaugeValue, float64(states[st]), state)
+
+		for port, counts := range portStates {
+			ch <- prometheus.MustNewConstMetric(c.descs["port_states"], prometheus.GaugeValue, float64(counts[st]), strconv.Itoa(port), state)
+		}
+	}
+	return nil
+}
+
+// count tallies connections by state, and by state per configured local port
+func (c *tcpStatCollector) count(connections procfs.NetTCP, states map[uint64]int, portStates map[int]map[uint64]int) {
+	for _, conn := range connections {
+		states[conn.St]++
+
+		if counts, ok := portStates[int(conn.LocalPort)]; ok {
+			counts[conn.St]++
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// procNetTCP renders a /proc/net/tcp file with one line per local port and state
+func procNetTCP(localAddr string, sockets [][2]int) string {
+	var b strings.Builder
+	b.WriteString("  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n")
+	for i, socket := range sockets {
+		fmt.Fprintf(&b, "%4d: %s:%04X %s:0000 %02X 00000000:00000000 00:00000000 00000000  1000        0 %d 1 0000000000000000 100 0 0 10 0\n",
+			i, localAddr, socket[0], localAddr, socket[1], 1000+i)
+	}
+	return b.String()
+}
+
+func TestTCPStatCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "net/tcp", procNetTCP("0100007F", [][2]int{
+		{22, 0x0A},   // listen
+		{22, 0x01},   // established
+		{8080, 0x08}, // close_wait
+		{8080, 0x08}, // close_wait
+		{40000, 0x06},
+	}))
+	writeFixture(t, root, "net/tcp6", procNetTCP("00000000000000000000000001000000", [][2]int{
+		{8080, 0x01},
+		{8080, 0x08},
+	}))
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+	logger := zaptest.NewLogger(t)
+
+	t.Run("states", func(t *testing.T) {
+		families := gatherFromCollector(t, newTCPStatCollector(procFS, nil, logger))
+
+		states := families["node_tcp_connection_states"]
+		require.NotNil(t, states)
+		assert.Len(t, states.Metric, len(tcpStates), "every state is reported")
+		assert.Equal(t, float64(2), metricValue(t, states, map[string]string{"state": "established"}))
+		assert.Equal(t, float64(3), metricValue(t, states, map[string]string{"state": "close_wait"}))
+		assert.Equal(t, float64(1), metricValue(t, states, map[string]string{"state": "time_wait"}))
+		assert.Equal(t, float64(0), metricValue(t, states, map[string]string{"state": "syn_recv"}))
+		assert.NotContains(t, families, "node_tcp_port_connection_states")
+	})
+
+	t.Run("per port", func(t *testing.T) {
+		families := gatherFromCollector(t, newTCPStatCollector(procFS, []int{8080, 443}, logger))
+
+		ports := families["node_tcp_port_connection_states"]
+		require.NotNil(t, ports)
+		assert.Len(t, ports.Metric, 2*len(tcpStates))
+		assert.Equal(t, float64(3), metricValue(t, ports, map[string]string{"port": "8080", "state": "close_wait"}))
+		assert.Equal(t, float64(1), metricValue(t, ports, map[string]string{"port": "8080", "state": "established"}))
+		assert.Equal(t, float64(0), metricValue(t, ports, map[string]string{"port": "443", "state": "established"}))
+	})
+}
@@ -0,0 +1,215 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+// tripPointTempPattern matches the trip point temperature files of a thermal zone
+var tripPointTempPattern = regexp.MustCompile(`^trip_point_(\d+)_temp$`)
+
+// hwmonTempPattern matches the temperature inputs of a hwmon chip
+var hwmonTempPattern = regexp.MustCompile(`^(temp\d+)_input$`)
+
+func init() {
+	// Thermal zone, cooling device and hwmon temperature metrics using sysfs
+	registerCollector("thermal", true, newNoOptions, func(sc *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newThermalCollector(sc.sysPath, logger), nil
+	})
+}
+
+type thermalCollector struct {
+	sysPath string
+	logger  *zap.Logger
+	descs   map[string]*prometheus.Desc
+}
+
+// newThermalCollector creates a collector for thermal zones, cooling devices and
+// hwmon temperature sensors. Guests without any of them produce no metrics
+func newThermalCollector(sysPath string, logger *zap.Logger) *thermalCollector {
+	return &thermalCollector{sysPath: sysPath, logger: logger}
+}
+
+func (c *thermalCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = map[string]*prometheus.Desc{
+		"zone_temp":       prometheus.NewDesc("node_thermal_zone_temp_celsius", "Thermal zone temperature in degrees Celsius.", []string{"zone", "type"}, nil),
+		"trip_point_temp": prometheus.NewDesc("node_thermal_zone_trip_point_temp_celsius", "Thermal zone trip point temperature in degrees Celsius.", []string{"zone", "type", "trip_point", "trip_type"}, nil),
+		"cooling_cur":     prometheus.NewDesc("node_cooling_device_cur_state", "Current throttle state of the cooling device.", []string{"name", "type"}, nil),
+		"cooling_max":     prometheus.NewDesc("node_cooling_device_max_state", "Maximum throttle state of the cooling device.", []string{"name", "type"}, nil),
+		"hwmon_temp":      prometheus.NewDesc("node_hwmon_temp_celsius", "Hardware monitor temperature reading in degrees Celsius.", []string{"chip", "chip_name", "sensor", "label"}, nil),
+		"hwmon_temp_max":  prometheus.NewDesc("node_hwmon_temp_max_celsius", "Hardware monitor maximum temperature in degrees Celsius.", []string{"chip", "chip_name", "sensor", "label"}, nil),
+		"hwmon_temp_crit": prometheus.NewDesc("node_hwmon_temp_crit_celsius", "Hardware monitor critical temperature in degrees Celsius.", []string{"chip", "chip_name", "sensor", "label"}, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+// collect fails only when none of the zones, cooling devices and hwmon chips
+// produced a metric
+func (c *thermalCollector) collect(ch chan<- prometheus.Metric) error {
+	thermalDir := filepath.Join(c.sysPath, "class", "thermal")
+	var errs sourceErrors
+
+	zones, _ := filepath.Glob(filepath.Join(thermalDir, "thermal_zone[0-9]*"))
+	for _, zoneDir := range zones {
+		errs.add(c.emitZone(ch, zoneDir))
+	}
+
+	coolingDevices, _ := filepath.Glob(filepath.Join(thermalDir, "cooling_device[0-9]*"))
+	for _, deviceDir := range coolingDevices {
+		errs.add(c.emitCoolingDevice(ch, deviceDir))
+	}
+
+	chips, _ := filepath.Glob(filepath.Join(c.sysPath, "class", "hwmon", "hwmon[0-9]*"))
+	for _, chipDir := range chips {
+		errs.add(c.emitHwmonChip(ch, chipDir))
+	}
+
+	return errs.err()
+}
+
+// emitZone sends the temperature and trip points of one thermal zone; the
+// kernel reports all temperatures in millidegrees Celsius. It returns an
+// error when none of them could be read
+func (c *thermalCollector) emitZone(ch chan<- prometheus.Metric, zoneDir string) error {
+	zone := strings.TrimPrefix(filepath.Base(zoneDir), "thermal_zone")
+	zoneType := readSysfsString(filepath.Join(zoneDir, "type"))
+
+	// Some zones, such as disabled ACPI zones, fail to read with EINVAL or ENODATA
+	temp, tempErr := readIntFromFile(filepath.Join(zoneDir, "temp"))
+	if tempErr == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["zone_temp"], prometheus.GaugeValue, float64(temp)/1000, zone, zoneType)
+	} else {
+		c.logger.Debug("Failed to read thermal zone temperature", zap.String("zone", zone), zap.Error(tempErr))
+	}
+
+	entries, err := os.ReadDir(zoneDir)
+	if err != nil {
+		c.logger.Debug("Failed to list thermal zone trip points", zap.String("zone", zone), zap.Error(err))
+		if tempErr != nil {
+			return fmt.Errorf("failed to read thermal zone %s: %w", zone, tempErr)
+		}
+		return nil
+	}
+
+	tripPoints := 0
+
+	for _, entry := range entries {
+		match := tripPointTempPattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		temp, err := readIntFromFile(filepath.Join(zoneDir, entry.Name()))
+		if err != nil {
+			c.logger.Debug("Failed to read thermal zone trip point", zap.String("zone", zone), zap.String("trip_point", match[1]), zap.Error(err))
+			continue
+		}
+
+		tripType := readSysfsString(filepath.Join(zoneDir, "trip_point_"+match[1]+"_type"))
+		ch <- prometheus.MustNewConstMetric(c.descs["trip_point_temp"], prometheus.GaugeValue, float64(temp)/1000, zone, zoneType, match[1], tripType)
+		tripPoints++
+	}
+
+	if tempErr != nil && tripPoints == 0 {
+		return fmt.Errorf("failed to read thermal zone %s: %w", zone, tempErr)
+	}
+	return nil
+}
+
+// emitCoolingDevice sends the current and maximum throttle state of one
+// cooling device, returning an error when neither could be read
+func (c *thermalCollector) emitCoolingDevice(ch chan<- prometheus.Metric, deviceDir string) error {
+	name := strings.TrimPrefix(filepath.Base(deviceDir), "cooling_device")
+	deviceType := readSysfsString(filepath.Join(deviceDir, "type"))
+
+	curState, curErr := readIntFromFile(filepath.Join(deviceDir, "cur_state"))
+	if curErr == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["cooling_cur"], prometheus.GaugeValue, float64(curState), name, deviceType)
+	} else {
+		c.logger.Debug("Failed to read cooling device state", zap.String("name", name), zap.Error(curErr))
+	}
+
+	maxState, maxErr := readIntFromFile(filepath.Join(deviceDir, "max_state"))
+	if maxErr == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["cooling_max"], prometheus.GaugeValue, float64(maxState), name, deviceType)
+	} else {
+		c.logger.Debug("Failed to read cooling device max state", zap.String("name", name), zap.Error(maxErr))
+	}
+
+	if curErr != nil && maxErr != nil {
+		return fmt.Errorf("failed to read cooling device %s: %w", name, curErr)
+	}
+	return nil
+}
+
+// emitHwmonChip sends the temperature sensors of one hwmon chip. Older drivers
+// keep the sensor files in the device subdirectory rather than the chip
+// directory. It returns an error when the chip has temperature sensors but
+// none of them could be read
+func (c *thermalCollector) emitHwmonChip(ch chan<- prometheus.Metric, chipDir string) error {
+	chip := filepath.Base(chipDir)
+
+	sensorDir := chipDir
+	if _, err := os.Stat(filepath.Join(chipDir, "name")); err != nil {
+		sensorDir = filepath.Join(chipDir, "device")
+	}
+	chipName := readSysfsString(filepath.Join(sensorDir, "name"))
+
+	entries, err := os.ReadDir(sensorDir)
+	if err != nil {
+		c.logger.Debug("Failed to list hwmon sensors", zap.String("chip", chip), zap.Error(err))
+		return fmt.Errorf("failed to list hwmon sensors of %s: %w", chip, err)
+	}
+
+	var lastErr error
+	readings := 0
+	for _, entry := range entries {
+		match := hwmonTempPattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		sensor := match[1]
+		label := readSysfsString(filepath.Join(sensorDir, sensor+"_label"))
+
+		temp, err := readIntFromFile(filepath.Join(sensorDir, entry.Name()))
+		if err != nil {
+			c.logger.Debug("Failed to read hwmon temperature", zap.String("chip", chip), zap.String("sensor", sensor), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.descs["hwmon_temp"], prometheus.GaugeValue, float64(temp)/1000, chip, chipName, sensor, label)
+		readings++
+
+		if maxTemp, err := readIntFromFile(filepath.Join(sensorDir, sensor+"_max")); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.descs["hwmon_temp_max"], prometheus.GaugeValue, float64(maxTemp)/1000, chip, chipName, sensor, label)
+		}
+		if critTemp, err := readIntFromFile(filepath.Join(sensorDir, sensor+"_crit")); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.descs["hwmon_temp_crit"], prometheus.GaugeValue, float64(critTemp)/1000, chip, chipName, sensor, label)
+		}
+	}
+
+	if lastErr != nil && readings == 0 {
+		return fmt.Errorf("failed to read hwmon temperatures of %s: %w", chip, lastErr)
+	}
+	return nil
+}
+
+// readSysfsString reads a sysfs attribute, returning an empty string when it is missing
+func readSysfsString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
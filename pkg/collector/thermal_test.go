@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestThermalCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "class/thermal/thermal_zone0/type", "x86_pkg_temp\n")
+	writeFixture(t, root, "class/thermal/thermal_zone0/temp", "45000\n")
+	writeFixture(t, root, "class/thermal/thermal_zone0/trip_point_0_temp", "100000\n")
+	writeFixture(t, root, "class/thermal/thermal_zone0/trip_point_0_type", "critical\n")
+	writeFixture(t, root, "class/thermal/cooling_device0/type", "Processor\n")
+	writeFixture(t, root, "class/thermal/cooling_device0/cur_state", "2\n")
+	writeFixture(t, root, "class/thermal/cooling_device0/max_state", "10\n")
+	writeFixture(t, root, "class/hwmon/hwmon0/name", "coretemp\n")
+	writeFixture(t, root, "class/hwmon/hwmon0/temp1_input", "52000\n")
+	writeFixture(t, root, "class/hwmon/hwmon0/temp1_label", "Package id 0\n")
+	writeFixture(t, root, "class/hwmon/hwmon0/temp1_crit", "-5000\n")
+
+	families := gatherFromCollector(t, newThermalCollector(root, zaptest.NewLogger(t)))
+
+	assert.Equal(t, 45.0, metricValue(t, families["node_thermal_zone_temp_celsius"], map[string]string{"zone": "0", "type": "x86_pkg_temp"}))
+	assert.Equal(t, 100.0, metricValue(t, families["node_thermal_zone_trip_point_temp_celsius"], map[string]string{"trip_point": "0", "trip_type": "critical"}))
+	assert.Equal(t, 2.0, metricValue(t, families["node_cooling_device_cur_state"], map[string]string{"name": "0", "type": "Processor"}))
+	assert.Equal(t, 10.0, metricValue(t, families["node_cooling_device_max_state"], map[string]string{"name": "0"}))
+
+	hwmonLabels := map[string]string{"chip": "hwmon0", "chip_name": "coretemp", "sensor": "temp1", "label": "Package id 0"}
+	assert.Equal(t, 52.0, metricValue(t, families["node_hwmon_temp_celsius"], hwmonLabels))
+	assert.Equal(t, -5.0, metricValue(t, families["node_hwmon_temp_crit_celsius"], hwmonLabels))
+	assert.NotContains(t, families, "node_hwmon_temp_max_celsius")
+}
+
+func TestThermalCollectorFailure(t *testing.T) {
+	root := t.TempDir()
+	// A disabled zone fails to read its temperature and has no trip points
+	writeFixture(t, root, "class/thermal/thermal_zone0/type", "acpitz\n")
+	writeFixture(t, root, "class/thermal/thermal_zone0/temp", "invalid\n")
+	c := newThermalCollector(root, zaptest.NewLogger(t))
+
+	families, err := gatherWithError(t, c)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read thermal zone 0")
+	assert.Empty(t, families)
+
+	// It does not fail the collection while another zone can be read
+	writeFixture(t, root, "class/thermal/thermal_zone1/temp", "45000\n")
+	families, err = gatherWithError(t, c)
+	assert.NoError(t, err)
+	assert.Equal(t, 45.0, metricValue(t, families["node_thermal_zone_temp_celsius"], map[string]string{"zone": "1"}))
+}
+
+func TestThermalCollectorNoZones(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	families := gatherFromCollector(t, newThermalCollector(t.TempDir(), zap.New(core)))
+
+	assert.Empty(t, families)
+	assert.Zero(t, logs.Len())
+}
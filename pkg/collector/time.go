@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+// timexStatus holds the kernel clock discipline state reported by adjtimex
+type timexStatus struct {
+	offset   float64
+	maxError float64
+	estError float64
+	synced   bool
+}
+
+func init() {
+	// System time and kernel clock synchronization status. The system clock
+	// is not namespaced, so no host path is needed to report the host's
+	registerCollector("time", true, newNoOptions, func(_ *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newTimeCollector(logger), nil
+	})
+}
+
+type timeCollector struct {
+	logger *zap.Logger
+	descs  map[string]*prometheus.Desc
+}
+
+func newTimeCollector(logger *zap.Logger) *timeCollector {
+	return &timeCollector{logger: logger}
+}
+
+func (c *timeCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = map[string]*prometheus.Desc{
+		"time":        prometheus.NewDesc("node_time_seconds", "System time in seconds since epoch (1970).", nil, nil),
+		"offset":      prometheus.NewDesc("node_timex_offset_seconds", "Time offset in between local system and reference clock.", nil, nil),
+		"sync_status": prometheus.NewDesc("node_timex_sync_status", "Is clock synchronized to a reliable server (1 = yes, 0 = no).", nil, nil),
+		"max_error":   prometheus.NewDesc("node_timex_maxerror_seconds", "Maximum error in seconds.", nil, nil),
+		"est_error":   prometheus.NewDesc("node_timex_estimated_error_seconds", "Estimated error in seconds.", nil, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *timeCollector) collect(ch chan<- prometheus.Metric) error {
+	now := time.Now()
+	ch <- prometheus.MustNewConstMetric(c.descs["time"], prometheus.GaugeValue, float64(now.UnixNano())/1e9)
+
+	status, err := readTimex()
+	if errors.Is(err, errCollectorUnavailable) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get timex status: %w", err)
+	}
+
+	syncStatus := 0.0
+	if status.synced {
+		syncStatus = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.descs["offset"], prometheus.GaugeValue, status.offset)
+	ch <- prometheus.MustNewConstMetric(c.descs["sync_status"], prometheus.GaugeValue, syncStatus)
+	ch <- prometheus.MustNewConstMetric(c.descs["max_error"], prometheus.GaugeValue, status.maxError)
+	ch <- prometheus.MustNewConstMetric(c.descs["est_error"], prometheus.GaugeValue, status.estError)
+	return nil
+}
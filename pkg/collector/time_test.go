@@ -0,0 +1,16 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestTimeCollector(t *testing.T) {
+	before := float64(time.Now().Unix())
+	families := gatherFromCollector(t, newTimeCollector(zaptest.NewLogger(t)))
+
+	assert.GreaterOrEqual(t, metricValue(t, families["node_time_seconds"], nil), before)
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package collector
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// readTimex queries the kernel clock without modifying it. The offset is in
+// nanoseconds when STA_NANO is set and in microseconds otherwise; the error
+// bounds are always in microseconds
+func readTimex() (*timexStatus, error) {
+	var timex unix.Timex
+	state, err := unix.Adjtimex(&timex)
+	if err != nil {
+		return nil, err
+	}
+
+	offsetUnit := 1e-6
+	if timex.Status&unix.STA_NANO != 0 {
+		offsetUnit = 1e-9
+	}
+
+	return &timexStatus{
+		offset:   float64(timex.Offset) * offsetUnit,
+		maxError: float64(timex.Maxerror) * 1e-6,
+		estError: float64(timex.Esterror) * 1e-6,
+		synced:   state != unix.TIME_ERROR && timex.Status&unix.STA_UNSYNC == 0,
+	}, nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package collector
+
+import (
+	"fmt"
+)
+
+// readTimex reports the clock state as unavailable, since adjtimex is Linux-specific
+func readTimex() (*timexStatus, error) {
+	return nil, fmt.Errorf("%w: adjtimex is only supported on Linux", errCollectorUnavailable)
+}
@@ -0,0 +1,55 @@
+//go:build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+func init() {
+	// Kernel and host identification from the uname system call. It ignores
+	// the procfs path: the kernel fields are the host's even in a container,
+	// while nodename and domainname come from the agent's UTS namespace, as
+	// they would when read from <procfs>/sys/kernel
+	registerCollector("uname", true, newNoOptions, func(_ *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newUnameCollector(logger)
+	})
+}
+
+type unameCollector struct {
+	logger *zap.Logger
+	desc   *prometheus.Desc
+}
+
+func newUnameCollector(logger *zap.Logger) (*unameCollector, error) {
+	return &unameCollector{logger: logger}, nil
+}
+
+func (c *unameCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.desc = prometheus.NewDesc("node_uname_info", "Labeled system information as provided by the uname system call.",
+		[]string{"sysname", "release", "version", "machine", "nodename", "domainname"}, nil)
+
+	ch <- c.desc
+}
+
+func (c *unameCollector) collect(ch chan<- prometheus.Metric) error {
+	var utsname unix.Utsname
+	if err := unix.Uname(&utsname); err != nil {
+		return fmt.Errorf("failed to get uname: %w", err)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1,
+		unix.ByteSliceToString(utsname.Sysname[:]),
+		unix.ByteSliceToString(utsname.Release[:]),
+		unix.ByteSliceToString(utsname.Version[:]),
+		unix.ByteSliceToString(utsname.Machine[:]),
+		unix.ByteSliceToString(utsname.Nodename[:]),
+		unix.ByteSliceToString(utsname.Domainname[:]),
+	)
+	return nil
+}
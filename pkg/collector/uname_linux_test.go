@@ -0,0 +1,22 @@
+//go:build linux
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestUnameCollector(t *testing.T) {
+	c, err := newUnameCollector(zaptest.NewLogger(t))
+	require.NoError(t, err)
+	families := gatherFromCollector(t, c)
+
+	require.Len(t, families["node_uname_info"].Metric, 1)
+	metric := families["node_uname_info"].Metric[0]
+	assert.Equal(t, "Linux", labelValue(metric, "sysname"))
+	assert.NotEmpty(t, labelValue(metric, "release"))
+}
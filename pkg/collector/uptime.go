@@ -0,0 +1,88 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// Boot time and uptime using procfs
+	registerCollector("uptime", true, newNoOptions, func(sc *SystemCollector, _ config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newUptimeCollector(sc.procFS, sc.procPath, logger), nil
+	})
+}
+
+type uptimeCollector struct {
+	procFS   procfs.FS
+	procPath string
+	logger   *zap.Logger
+	descs    map[string]*prometheus.Desc
+}
+
+func newUptimeCollector(procFS procfs.FS, procPath string, logger *zap.Logger) *uptimeCollector {
+	return &uptimeCollector{procFS: procFS, procPath: procPath, logger: logger}
+}
+
+func (c *uptimeCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.descs = map[string]*prometheus.Desc{
+		"boot_time": prometheus.NewDesc("node_boot_time_seconds", "Node boot time, in unixtime.", nil, nil),
+		"uptime":    prometheus.NewDesc("node_uptime_seconds", "Number of seconds since the node booted.", nil, nil),
+	}
+
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *uptimeCollector) collect(ch chan<- prometheus.Metric) error {
+	var errs sourceErrors
+
+	stat, err := c.procFS.Stat()
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["boot_time"], prometheus.GaugeValue, float64(stat.BootTime))
+	} else {
+		c.logger.Debug("Failed to get boot time", zap.Error(err))
+		err = fmt.Errorf("failed to get boot time: %w", err)
+	}
+	errs.add(err)
+
+	uptime, err := readUptime(filepath.Join(c.procPath, "uptime"))
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(c.descs["uptime"], prometheus.GaugeValue, uptime)
+	} else {
+		c.logger.Debug("Failed to get uptime", zap.Error(err))
+		err = fmt.Errorf("failed to get uptime: %w", err)
+	}
+	errs.add(err)
+
+	return errs.err()
+}
+
+// readUptime returns the first field of /proc/uptime, the seconds since boot;
+// the second field is the idle time summed over all CPUs
+func readUptime(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%s is empty", path)
+	}
+
+	uptime, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return uptime, nil
+}
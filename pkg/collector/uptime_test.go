@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestUptimeCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "stat", `cpu  100 0 100 1000 0 0 0 0 0 0
+cpu0 100 0 100 1000 0 0 0 0 0 0
+intr 0
+ctxt 0
+btime 1700000000
+processes 1
+procs_running 1
+procs_blocked 0
+`)
+	writeFixture(t, root, "uptime", "12345.67 45678.90\n")
+
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+
+	families := gatherFromCollector(t, newUptimeCollector(procFS, root, zaptest.NewLogger(t)))
+
+	assert.Equal(t, float64(1700000000), metricValue(t, families["node_boot_time_seconds"], nil))
+	assert.Equal(t, 12345.67, metricValue(t, families["node_uptime_seconds"], nil))
+}
+
+func TestUptimeCollectorFailure(t *testing.T) {
+	root := t.TempDir()
+	procFS, err := procfs.NewFS(root)
+	require.NoError(t, err)
+	c := newUptimeCollector(procFS, root, zaptest.NewLogger(t))
+
+	// Either file alone still produces a metric
+	writeFixture(t, root, "uptime", "12345.67 45678.90\n")
+	families, err := gatherWithError(t, c)
+	assert.NoError(t, err)
+	assert.Contains(t, families, "node_uptime_seconds")
+
+	require.NoError(t, os.Remove(filepath.Join(root, "uptime")))
+	_, err = gatherWithError(t, c)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get boot time")
+	assert.Contains(t, err.Error(), "failed to get uptime")
+}
@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/strettch/sc-metrics-agent/pkg/config"
+	"go.uber.org/zap"
+)
+
+// defaultVMStatFields selects the paging, swapping, page fault and OOM kill counters from /proc/vmstat
+const defaultVMStatFields = `^(oom_kill|pgpg|pswp|pg.*fault).*`
+
+// vmstatOptions configures the vmstat collector
+type vmstatOptions struct {
+	// Fields selects the /proc/vmstat fields to report as node_vmstat_<field>
+	Fields string `yaml:"fields" json:"fields"`
+}
+
+func newVMStatOptions() config.CollectorOptions {
+	return &vmstatOptions{Fields: defaultVMStatFields}
+}
+
+func (o *vmstatOptions) Validate() error {
+	return validatePattern("fields", o.Fields)
+}
+
+func init() {
+	// Virtual memory statistics from /proc/vmstat
+	registerCollector("vmstat", true, newVMStatOptions, func(sc *SystemCollector, options config.CollectorOptions, logger *zap.Logger) (metricsCollector, error) {
+		return newVMStatCollector(sc.procPath, options.(*vmstatOptions).Fields, logger)
+	})
+}
+
+type vmstatCollector struct {
+	procPath    string
+	fieldFilter *regexp.Regexp
+	logger      *zap.Logger
+}
+
+// newVMStatCollector creates a /proc/vmstat collector that only reports fields
+// matching the given pattern, falling back to the default field set when empty
+func newVMStatCollector(procPath, fields string, logger *zap.Logger) (*vmstatCollector, error) {
+	if fields == "" {
+		fields = defaultVMStatFields
+	}
+
+	fieldFilter, err := regexp.Compile(fields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vmstat field pattern %q: %w", fields, err)
+	}
+
+	return &vmstatCollector{procPath: procPath, fieldFilter: fieldFilter, logger: logger}, nil
+}
+
+// Describe sends no descriptors: the metric set depends on the fields the
+// running kernel exposes, so the collector is registered as unchecked
+func (c *vmstatCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func (c *vmstatCollector) collect(ch chan<- prometheus.Metric) error {
+	file, err := os.Open(filepath.Join(c.procPath, "vmstat"))
+	if err != nil {
+		return fmt.Errorf("failed to open vmstat: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) != 2 || !c.fieldFilter.MatchString(parts[0]) {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			c.logger.Debug("Failed to parse vmstat field", zap.String("field", parts[0]), zap.Error(err))
+			continue
+		}
+
+		desc := prometheus.NewDesc(
+			"node_vmstat_"+parts[0],
+			fmt.Sprintf("/proc/vmstat information field %s.", parts[0]),
+			nil, nil,
+		)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.UntypedValue, value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read vmstat: %w", err)
+	}
+	return nil
+}
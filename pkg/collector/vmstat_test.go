@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+const vmstatFixture = `nr_free_pages 123456
+nr_dirty 42
+pgpgin 1000
+pgpgout 2000
+pswpin 3
+pswpout 4
+pgfault 500000
+pgmajfault 12
+oom_kill 2
+`
+
+func TestVMStatCollector(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "vmstat", vmstatFixture)
+
+	c, err := newVMStatCollector(root, "", zaptest.NewLogger(t))
+	require.NoError(t, err)
+	families := gatherFromCollector(t, c)
+
+	assert.Len(t, families, 7)
+	assert.Equal(t, float64(1000), metricValue(t, families["node_vmstat_pgpgin"], nil))
+	assert.Equal(t, float64(4), metricValue(t, families["node_vmstat_pswpout"], nil))
+	assert.Equal(t, float64(12), metricValue(t, families["node_vmstat_pgmajfault"], nil))
+	assert.Equal(t, float64(2), metricValue(t, families["node_vmstat_oom_kill"], nil))
+	assert.NotContains(t, families, "node_vmstat_nr_dirty")
+}
+
+func TestVMStatCollectorCustomFields(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, "vmstat", vmstatFixture)
+
+	c, err := newVMStatCollector(root, "^nr_", zaptest.NewLogger(t))
+	require.NoError(t, err)
+	families := gatherFromCollector(t, c)
+
+	assert.Len(t, families, 2)
+	assert.Equal(t, float64(42), metricValue(t, families["node_vmstat_nr_dirty"], nil))
+
+	_, err = newVMStatCollector(root, "^(pgpg", zaptest.NewLogger(t))
+	assert.Error(t, err)
+}
@@ -0,0 +1,333 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CollectorOptions holds the options of one collector. Each collector has its
+// own options struct, whose yaml tags name the options accepted under the
+// collector in the configuration file. Fields may be bool, int, string,
+// time.Duration, []string or []int
+type CollectorOptions interface {
+	// Validate checks the options of an enabled collector
+	Validate() error
+}
+
+// CollectorEntry configures one collector
+type CollectorEntry struct {
+	Enabled bool `json:"enabled"`
+	// Interval collects the collector at its own interval instead of every
+	// collection_interval when positive
+	Interval time.Duration    `json:"interval,omitempty"`
+	Options  CollectorOptions `json:"options"`
+}
+
+// collectorSpec describes a registered collector
+type collectorSpec struct {
+	enabled    bool
+	newOptions func() CollectorOptions
+}
+
+// collectorSpecs holds the registered collectors by name
+var collectorSpecs = make(map[string]collectorSpec)
+
+// ignoredCollectorKeys are collector settings of earlier versions that never
+// selected a collector, mapped to the collector that replaces them. They are
+// still accepted so existing files load, with a deprecation notice
+var ignoredCollectorKeys = map[string]string{"disk": "diskstats", "network": "netdev"}
+
+// RegisterCollector makes a collector configurable under name. enabled sets
+// whether it runs when the configuration does not mention it, and newOptions
+// returns a pointer to its options struct filled with the defaults. It is
+// meant to be called from init functions and panics on invalid registrations
+func RegisterCollector(name string, enabled bool, newOptions func() CollectorOptions) {
+	if _, ok := collectorSpecs[name]; ok {
+		panic(fmt.Sprintf("collector %s registered twice", name))
+	}
+
+	options := newOptions()
+	value := reflect.ValueOf(options)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("options of collector %s must be a pointer to a struct, not %T", name, options))
+	}
+	for _, field := range optionFields(options) {
+		if !supportedOptionType(field.value) {
+			panic(fmt.Sprintf("option %s of collector %s has unsupported type %s", field.key, name, field.value.Type()))
+		}
+	}
+
+	collectorSpecs[name] = collectorSpec{enabled: enabled, newOptions: newOptions}
+}
+
+// RegisteredCollectors returns the names of the registered collectors, sorted
+func RegisteredCollectors() []string {
+	names := make([]string, 0, len(collectorSpecs))
+	for name := range collectorSpecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewCollectorConfig returns the default configuration of the registered
+// collectors
+func NewCollectorConfig() CollectorConfig {
+	cc := CollectorConfig{
+		SelfMetrics: true,
+		Timeout:     DefaultCollectorTimeout,
+		Entries:     make(map[string]*CollectorEntry, len(collectorSpecs)),
+	}
+	for name, spec := range collectorSpecs {
+		cc.Entries[name] = &CollectorEntry{Enabled: spec.enabled, Options: spec.newOptions()}
+	}
+	return cc
+}
+
+// Enabled reports whether the named collector is enabled
+func (cc *CollectorConfig) Enabled(name string) bool {
+	entry, ok := cc.Entries[name]
+	return ok && entry.Enabled
+}
+
+// String lists the enabled collectors along with the shared settings
+func (cc CollectorConfig) String() string {
+	var enabled []string
+	for _, name := range RegisteredCollectors() {
+		if cc.Enabled(name) {
+			enabled = append(enabled, name)
+		}
+	}
+	return fmt.Sprintf("{Enabled:%v SelfMetrics:%t Timeout:%s}", enabled, cc.SelfMetrics, cc.Timeout)
+}
+
+// entry returns the configuration of a registered collector, adding its
+// defaults when the configuration has none yet
+func (cc *CollectorConfig) entry(name string) (*CollectorEntry, bool) {
+	if entry, ok := cc.Entries[name]; ok {
+		return entry, true
+	}
+
+	spec, ok := collectorSpecs[name]
+	if !ok {
+		return nil, false
+	}
+
+	if cc.Entries == nil {
+		cc.Entries = make(map[string]*CollectorEntry)
+	}
+	entry := &CollectorEntry{Enabled: spec.enabled, Options: spec.newOptions()}
+	cc.Entries[name] = entry
+	return entry, true
+}
+
+// UnmarshalYAML applies the collectors section of the configuration file on
+// top of the current settings. Each collector takes a boolean or a mapping of
+// enabled, interval and its options:
+//
+//	cpu: true
+//	memory:
+//	  enabled: true
+//	  exclude: "^Hugepages"
+func (cc *CollectorConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: collectors must be a mapping", value.Line)
+	}
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key, node := value.Content[i].Value, value.Content[i+1]
+
+		var err error
+		switch key {
+		case "self_metrics":
+			err = node.Decode(&cc.SelfMetrics)
+		case "timeout":
+			err = node.Decode(&cc.Timeout)
+		case "intervals":
+			err = cc.decodeIntervals(node)
+		default:
+			if entry, ok := cc.entry(key); ok {
+				err = decodeCollectorEntry(key, entry, node)
+			} else if replacement, ok := ignoredCollectorKeys[key]; ok {
+				cc.deprecate(fmt.Sprintf("collectors.%s (line %d)", key, node.Line), replacement)
+			} else {
+				err = fmt.Errorf("line %d: unknown collector %s", node.Line, key)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deprecate records that setting is ignored in favor of the replacement collector
+func (cc *CollectorConfig) deprecate(setting, replacement string) {
+	cc.Deprecations = append(cc.Deprecations,
+		fmt.Sprintf("%s is deprecated and ignored; configure the %s collector instead", setting, replacement))
+}
+
+// decodeIntervals applies the intervals mapping of collector names to durations
+func (cc *CollectorConfig) decodeIntervals(node *yaml.Node) error {
+	var intervals map[string]time.Duration
+	if err := node.Decode(&intervals); err != nil {
+		return err
+	}
+
+	for name, interval := range intervals {
+		entry, ok := cc.entry(name)
+		if !ok {
+			return fmt.Errorf("line %d: unknown collector %s in intervals", node.Line, name)
+		}
+		entry.Interval = interval
+	}
+	return nil
+}
+
+// decodeCollectorEntry applies the boolean or mapping form of a collector
+func decodeCollectorEntry(name string, entry *CollectorEntry, node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&entry.Enabled)
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: collector %s must be a boolean or a mapping", node.Line, name)
+	}
+
+	fields := make(map[string]reflect.Value)
+	for _, field := range optionFields(entry.Options) {
+		fields[field.key] = field.value
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, valueNode := node.Content[i].Value, node.Content[i+1]
+
+		var err error
+		switch key {
+		case "enabled":
+			err = valueNode.Decode(&entry.Enabled)
+		case "interval":
+			err = valueNode.Decode(&entry.Interval)
+		default:
+			field, ok := fields[key]
+			if !ok {
+				return fmt.Errorf("line %d: unknown option %s of collector %s", valueNode.Line, key, name)
+			}
+			err = valueNode.Decode(field.Addr().Interface())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadEnv applies SC_COLLECTOR_<NAME> to enable or disable each registered
+// collector and SC_COLLECTOR_<NAME>_<KEY> to set its options. Invalid values
+// are ignored
+func (cc *CollectorConfig) loadEnv() {
+	for _, key := range sortedKeys(ignoredCollectorKeys) {
+		env := "SC_COLLECTOR_" + strings.ToUpper(key)
+		if os.Getenv(env) != "" {
+			cc.deprecate(env, ignoredCollectorKeys[key])
+		}
+	}
+
+	for _, name := range RegisteredCollectors() {
+		entry, _ := cc.entry(name)
+
+		if val := os.Getenv("SC_COLLECTOR_" + strings.ToUpper(name)); val != "" {
+			if enabled, err := strconv.ParseBool(val); err == nil {
+				entry.Enabled = enabled
+			}
+		}
+
+		for _, field := range optionFields(entry.Options) {
+			if val := os.Getenv("SC_COLLECTOR_" + strings.ToUpper(name+"_"+field.key)); val != "" {
+				setOption(field.value, val)
+			}
+		}
+	}
+}
+
+// sortedKeys returns the keys of m, sorted
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// optionField is one option of a collector's options struct
+type optionField struct {
+	// key is the name of the option under the collector
+	key   string
+	value reflect.Value
+}
+
+// optionFields lists the options of a collector's options struct
+func optionFields(options CollectorOptions) []optionField {
+	value := reflect.ValueOf(options).Elem()
+
+	var fields []optionField
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		key, _, _ := strings.Cut(structField.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" || !structField.IsExported() {
+			continue
+		}
+		fields = append(fields, optionField{key: key, value: value.Field(i)})
+	}
+	return fields
+}
+
+// supportedOptionType reports whether setOption can parse the option
+func supportedOptionType(value reflect.Value) bool {
+	switch value.Addr().Interface().(type) {
+	case *bool, *int, *string, *time.Duration, *[]string, *[]int:
+		return true
+	default:
+		return false
+	}
+}
+
+// setOption parses an environment variable into an option, leaving it
+// unchanged when the value is invalid. Lists are comma-separated
+func setOption(value reflect.Value, val string) {
+	switch option := value.Addr().Interface().(type) {
+	case *bool:
+		if parsed, err := strconv.ParseBool(val); err == nil {
+			*option = parsed
+		}
+	case *int:
+		if parsed, err := strconv.Atoi(val); err == nil {
+			*option = parsed
+		}
+	case *string:
+		*option = val
+	case *time.Duration:
+		if parsed, err := time.ParseDuration(val); err == nil {
+			*option = parsed
+		}
+	case *[]string:
+		*option = parseList(val)
+	case *[]int:
+		var items []int
+		for _, item := range parseList(val) {
+			if parsed, err := strconv.Atoi(item); err == nil {
+				items = append(items, parsed)
+			}
+		}
+		*option = items
+	}
+}
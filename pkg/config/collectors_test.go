@@ -0,0 +1,204 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// testOptions is the options type of the collectors registered for the tests
+type testOptions struct{}
+
+func (o *testOptions) Validate() error {
+	return nil
+}
+
+type testCPUOptions struct {
+	PerCPU   bool `yaml:"per_cpu"`
+	Extended bool `yaml:"extended"`
+}
+
+func (o *testCPUOptions) Validate() error {
+	return nil
+}
+
+type testProcessTopOptions struct {
+	TopN      int           `yaml:"top_n"`
+	AllowList []string      `yaml:"allow_list"`
+	Ports     []int         `yaml:"ports"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+func (o *testProcessTopOptions) Validate() error {
+	if o.TopN <= 0 {
+		return fmt.Errorf("top_n must be positive")
+	}
+	return nil
+}
+
+func init() {
+	newTestOptions := func() CollectorOptions { return &testOptions{} }
+	RegisterCollector("processes", true, newTestOptions)
+	RegisterCollector("uname", true, newTestOptions)
+	RegisterCollector("filesystem", true, newTestOptions)
+	RegisterCollector("cpu_freq", false, newTestOptions)
+	RegisterCollector("cpu", true, func() CollectorOptions { return &testCPUOptions{Extended: true} })
+	RegisterCollector("process_top", true, func() CollectorOptions { return &testProcessTopOptions{TopN: 10} })
+}
+
+func TestRegisterCollector(t *testing.T) {
+	assert.Equal(t, []string{"cpu", "cpu_freq", "filesystem", "process_top", "processes", "uname"}, RegisteredCollectors())
+
+	assert.Panics(t, func() {
+		RegisterCollector("cpu", true, func() CollectorOptions { return &testOptions{} })
+	}, "duplicate name")
+	assert.Panics(t, func() {
+		RegisterCollector("invalid", true, func() CollectorOptions { return invalidOptions(0) })
+	}, "options that are not a struct")
+	assert.Panics(t, func() {
+		RegisterCollector("invalid", true, func() CollectorOptions { return &mapOptions{} })
+	}, "unsupported option type")
+}
+
+type invalidOptions int
+
+func (o invalidOptions) Validate() error {
+	return nil
+}
+
+type mapOptions struct {
+	Sizes map[string]int `yaml:"sizes"`
+}
+
+func (o *mapOptions) Validate() error {
+	return nil
+}
+
+func TestNewCollectorConfig(t *testing.T) {
+	cc := NewCollectorConfig()
+
+	assert.True(t, cc.Enabled("cpu"))
+	assert.False(t, cc.Enabled("cpu_freq"))
+	assert.False(t, cc.Enabled("unknown"))
+	assert.Equal(t, &testCPUOptions{Extended: true}, cc.Entries["cpu"].Options)
+	assert.True(t, cc.SelfMetrics)
+	assert.Equal(t, DefaultCollectorTimeout, cc.Timeout)
+
+	// Entries are independent of other configurations
+	cc.Entries["cpu"].Options.(*testCPUOptions).PerCPU = true
+	assert.False(t, NewCollectorConfig().Entries["cpu"].Options.(*testCPUOptions).PerCPU)
+}
+
+func TestCollectorConfigUnmarshalYAML(t *testing.T) {
+	data := `
+collectors:
+  processes: false
+  cpu_freq: true
+  cpu:
+    enabled: true
+    per_cpu: true
+    interval: 1m
+  process_top:
+    top_n: 5
+    ports: [22, 443]
+    timeout: 2s
+  disk: true
+  network: true
+  timeout: 3s
+  self_metrics: false
+  intervals:
+    uname: 1h
+`
+	cfg := Config{Collectors: NewCollectorConfig()}
+	require.NoError(t, yaml.Unmarshal([]byte(data), &cfg))
+	cc := cfg.Collectors
+
+	// Boolean form
+	assert.False(t, cc.Enabled("processes"))
+	assert.True(t, cc.Enabled("cpu_freq"))
+
+	// Mapping form, keeping the defaults of options that are not set
+	assert.True(t, cc.Enabled("cpu"))
+	assert.Equal(t, time.Minute, cc.Entries["cpu"].Interval)
+	assert.Equal(t, &testCPUOptions{PerCPU: true, Extended: true}, cc.Entries["cpu"].Options)
+
+	assert.Equal(t, &testProcessTopOptions{TopN: 5, Ports: []int{22, 443}, Timeout: 2 * time.Second}, cc.Entries["process_top"].Options)
+	assert.True(t, cc.Enabled("process_top"))
+	assert.Equal(t, time.Hour, cc.Entries["uname"].Interval)
+
+	assert.Equal(t, 3*time.Second, cc.Timeout)
+	assert.False(t, cc.SelfMetrics)
+	assert.True(t, cc.Enabled("filesystem"), "collectors that are not mentioned keep their default")
+	assert.Equal(t, []string{
+		"collectors.disk (line 13) is deprecated and ignored; configure the diskstats collector instead",
+		"collectors.network (line 14) is deprecated and ignored; configure the netdev collector instead",
+	}, cc.Deprecations)
+
+	invalid := []struct {
+		data string
+		err  string
+	}{
+		{"collectors:\n  cpuu: true\n", "unknown collector cpuu"},
+		{"collectors:\n  process_top_n: 5\n", "unknown collector process_top_n"},
+		{"collectors:\n  cpu:\n    per_core: true\n", "unknown option per_core of collector cpu"},
+		{"collectors:\n  cpu: [true]\n", "collector cpu must be a boolean or a mapping"},
+		{"collectors:\n  cpu: maybe\n", "cannot unmarshal"},
+		{"collectors:\n  intervals:\n    cpuu: 1h\n", "unknown collector cpuu in intervals"},
+		{"collectors: true\n", "collectors must be a mapping"},
+	}
+	for _, tc := range invalid {
+		cfg := Config{Collectors: NewCollectorConfig()}
+		err := yaml.Unmarshal([]byte(tc.data), &cfg)
+		require.Error(t, err, tc.data)
+		assert.Contains(t, err.Error(), tc.err)
+	}
+}
+
+func TestCollectorConfigLoadEnv(t *testing.T) {
+	t.Setenv("SC_COLLECTOR_CPU", "false")
+	t.Setenv("SC_COLLECTOR_CPU_PER_CPU", "true")
+	t.Setenv("SC_COLLECTOR_CPU_EXTENDED", "invalid")
+	t.Setenv("SC_COLLECTOR_PROCESS_TOP_TOP_N", "7")
+	t.Setenv("SC_COLLECTOR_PROCESS_TOP_ALLOW_LIST", "nginx, postgres")
+	t.Setenv("SC_COLLECTOR_PROCESS_TOP_PORTS", "22,x,443")
+	t.Setenv("SC_COLLECTOR_PROCESS_TOP_TIMEOUT", "4s")
+	t.Setenv("SC_COLLECTOR_NETWORK", "false")
+
+	cc := NewCollectorConfig()
+	cc.loadEnv()
+
+	assert.False(t, cc.Enabled("cpu"))
+	assert.Equal(t, &testCPUOptions{PerCPU: true, Extended: true}, cc.Entries["cpu"].Options)
+	assert.Equal(t, &testProcessTopOptions{
+		TopN:      7,
+		AllowList: []string{"nginx", "postgres"},
+		Ports:     []int{22, 443},
+		Timeout:   4 * time.Second,
+	}, cc.Entries["process_top"].Options)
+	assert.Equal(t, []string{
+		"SC_COLLECTOR_NETWORK is deprecated and ignored; configure the netdev collector instead",
+	}, cc.Deprecations)
+}
+
+func TestCollectorConfigValidate(t *testing.T) {
+	cc := NewCollectorConfig()
+	require.NoError(t, cc.validate())
+
+	cc.Entries["process_top"].Options.(*testProcessTopOptions).TopN = 0
+	err := cc.validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid options of collector process_top: top_n must be positive")
+
+	// Options are ignored when the collector is disabled
+	cc.Entries["process_top"].Enabled = false
+	assert.NoError(t, cc.validate())
+
+	cc.Entries["unknown"] = &CollectorEntry{Enabled: true, Options: &testOptions{}}
+	err = cc.validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown collector unknown")
+}
@@ -6,6 +6,8 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +24,9 @@ type AgentConfig struct {
 
 const agentConfigPath = "/etc/strettchcloud/config/agent.yaml"
 
+// DefaultCollectorTimeout bounds each collector's collection before its results are dropped
+const DefaultCollectorTimeout = 10 * time.Second
+
 // Config represents the agent configuration
 type Config struct {
 	// Collection settings
@@ -38,6 +43,12 @@ type Config struct {
 	// Collector configuration
 	Collectors CollectorConfig `yaml:"collectors" json:"collectors"`
 
+	// Host filesystem locations, for running in a container with the host's
+	// /proc, /sys and / mounted elsewhere (e.g. /host/proc)
+	ProcfsPath string `yaml:"procfs_path" json:"procfs_path"`
+	SysfsPath  string `yaml:"sysfs_path" json:"sysfs_path"`
+	RootfsPath string `yaml:"rootfs_path" json:"rootfs_path"`
+
 	// Logging
 	LogLevel string `yaml:"log_level" json:"log_level"`
 
@@ -49,42 +60,19 @@ type Config struct {
 	AgentVersion string `yaml:"agent_version" json:"agent_version"`
 }
 
-// CollectorConfig defines which collectors are enabled
+// CollectorConfig defines which collectors are enabled and their options.
+// Collectors register themselves with RegisterCollector; Entries holds the
+// configuration of each by name
 type CollectorConfig struct {
-	// Process metrics
-	Processes bool `yaml:"processes" json:"processes"`
-
-	// CPU metrics
-	CPU     bool `yaml:"cpu" json:"cpu"`
-	CPUFreq bool `yaml:"cpu_freq" json:"cpu_freq"`
-	LoadAvg bool `yaml:"loadavg" json:"loadavg"`
-
-	// Memory metrics
-	Memory bool `yaml:"memory" json:"memory"`
-	VMStat bool `yaml:"vmstat" json:"vmstat"`
-
-	// Storage metrics
-	Disk       bool `yaml:"disk" json:"disk"`
-	DiskStats  bool `yaml:"diskstats" json:"diskstats"`
-	Filesystem bool `yaml:"filesystem" json:"filesystem"`
-
-	// Network metrics
-	Network  bool `yaml:"network" json:"network"`
-	NetDev   bool `yaml:"netdev" json:"netdev"`
-	NetStat  bool `yaml:"netstat" json:"netstat"`
-	Sockstat bool `yaml:"sockstat" json:"sockstat"`
-
-	// System metrics
-	Uname      bool `yaml:"uname" json:"uname"`
-	Time       bool `yaml:"time" json:"time"`
-	Uptime     bool `yaml:"uptime" json:"uptime"`
-	Entropy    bool `yaml:"entropy" json:"entropy"`
-	Interrupts bool `yaml:"interrupts" json:"interrupts"`
-
-	// Additional metrics
-	Thermal   bool `yaml:"thermal" json:"thermal"`
-	Pressure  bool `yaml:"pressure" json:"pressure"`
-	Schedstat bool `yaml:"schedstat" json:"schedstat"`
+	Entries map[string]*CollectorEntry `yaml:"-" json:"collectors"`
+
+	// Agent self-monitoring
+	SelfMetrics bool          `yaml:"self_metrics" json:"self_metrics"`
+	Timeout     time.Duration `yaml:"timeout" json:"timeout"`
+
+	// Deprecations describes deprecated settings found while loading, which
+	// are ignored and should be logged as warnings
+	Deprecations []string `yaml:"-" json:"-"`
 }
 
 // readMetadataBaseURL reads the metadata service base URL from agent.yaml
@@ -132,46 +120,14 @@ func DefaultConfig() *Config {
 		MetadataServiceEndpoint: metadataEndpoint,
 		VMID:                    vmID,
 		Labels:                  make(map[string]string),
-		Collectors: CollectorConfig{
-			// Process metrics (required for this story)
-			Processes: true,
-
-			// CPU metrics
-			CPU:     true,
-			CPUFreq: true,
-			LoadAvg: true,
-
-			// Memory metrics
-			Memory: true,
-			VMStat: true,
-
-			// Storage metrics
-			Disk:       true,
-			DiskStats:  true,
-			Filesystem: true,
-
-			// Network metrics
-			Network:  true,
-			NetDev:   true,
-			NetStat:  true,
-			Sockstat: true,
-
-			// System metrics
-			Uname:      true,
-			Time:       true,
-			Uptime:     true,
-			Entropy:    true,
-			Interrupts: true,
-
-			// Additional metrics
-			Thermal:   true,
-			Pressure:  true,
-			Schedstat: true,
-		},
-		LogLevel:      "info",
-		MaxRetries:    3,
-		RetryInterval: 5 * time.Second,
-		AgentVersion:  detectAgentVersion(),
+		Collectors:              NewCollectorConfig(),
+		ProcfsPath:              "/proc",
+		SysfsPath:               "/sys",
+		RootfsPath:              "/",
+		LogLevel:                "info",
+		MaxRetries:              3,
+		RetryInterval:           5 * time.Second,
+		AgentVersion:            detectAgentVersion(),
 	}
 }
 
@@ -252,9 +208,37 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	cfg.scheduleCollectors()
+
 	return cfg, nil
 }
 
+// TickInterval returns how often collection runs: every collection_interval,
+// or at the shortest interval of an enabled collector when that is shorter
+func (c *Config) TickInterval() time.Duration {
+	tick := c.CollectionInterval
+	for _, entry := range c.Collectors.Entries {
+		if entry.Enabled && entry.Interval > 0 && entry.Interval < tick {
+			tick = entry.Interval
+		}
+	}
+	return tick
+}
+
+// scheduleCollectors gives the collectors without an interval of their own
+// the collection_interval when collection ticks more often, so that only the
+// collectors with a shorter interval run on every tick
+func (c *Config) scheduleCollectors() {
+	if c.TickInterval() == c.CollectionInterval {
+		return
+	}
+	for _, entry := range c.Collectors.Entries {
+		if entry.Interval == 0 {
+			entry.Interval = c.CollectionInterval
+		}
+	}
+}
+
 // loadFromFile loads configuration from a YAML file
 func (c *Config) loadFromFile(path string) error {
 	data, err := os.ReadFile(path)
@@ -300,6 +284,16 @@ func (c *Config) loadFromEnv() {
 		c.VMID = val
 	}
 
+	if val := os.Getenv("SC_PROCFS_PATH"); val != "" {
+		c.ProcfsPath = val
+	}
+	if val := os.Getenv("SC_SYSFS_PATH"); val != "" {
+		c.SysfsPath = val
+	}
+	if val := os.Getenv("SC_ROOTFS_PATH"); val != "" {
+		c.RootfsPath = val
+	}
+
 	if val := os.Getenv("SC_LOG_LEVEL"); val != "" {
 		c.LogLevel = val
 	}
@@ -330,109 +324,28 @@ func (c *Config) loadFromEnv() {
 
 // loadCollectorEnvVars loads collector configuration from environment variables
 func loadCollectorEnvVars(collectors *CollectorConfig) {
-	if val := os.Getenv("SC_COLLECTOR_PROCESSES"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Processes = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_CPU"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.CPU = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_CPU_FREQ"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.CPUFreq = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_LOADAVG"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.LoadAvg = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_MEMORY"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Memory = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_VMSTAT"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.VMStat = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_DISK"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Disk = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_DISKSTATS"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.DiskStats = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_FILESYSTEM"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Filesystem = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_NETWORK"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Network = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_NETDEV"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.NetDev = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_NETSTAT"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.NetStat = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_SOCKSTAT"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Sockstat = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_UNAME"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Uname = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_TIME"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Time = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_UPTIME"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Uptime = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_ENTROPY"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Entropy = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_INTERRUPTS"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Interrupts = enabled
-		}
-	}
-	if val := os.Getenv("SC_COLLECTOR_THERMAL"); val != "" {
+	collectors.loadEnv()
+
+	if val := os.Getenv("SC_COLLECTOR_SELF_METRICS"); val != "" {
 		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Thermal = enabled
+			collectors.SelfMetrics = enabled
 		}
 	}
-	if val := os.Getenv("SC_COLLECTOR_PRESSURE"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Pressure = enabled
+	if val := os.Getenv("SC_COLLECTOR_TIMEOUT"); val != "" {
+		if duration, err := time.ParseDuration(val); err == nil {
+			collectors.Timeout = duration
 		}
 	}
-	if val := os.Getenv("SC_COLLECTOR_SCHEDSTAT"); val != "" {
-		if enabled, err := strconv.ParseBool(val); err == nil {
-			collectors.Schedstat = enabled
+	// Format: name1=duration1,name2=duration2
+	if val := os.Getenv("SC_COLLECTOR_INTERVALS"); val != "" {
+		for name, value := range parseLabels(val) {
+			entry, ok := collectors.entry(name)
+			if !ok {
+				continue
+			}
+			if duration, err := time.ParseDuration(value); err == nil {
+				entry.Interval = duration
+			}
 		}
 	}
 }
@@ -456,6 +369,18 @@ func parseLabels(labelStr string) map[string]string {
 	return labels
 }
 
+// parseList parses a comma-separated list, dropping empty entries
+func parseList(listStr string) []string {
+	var items []string
+	for _, item := range strings.Split(listStr, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
 // validate checks if the configuration is valid
 func (c *Config) validate() error {
 	if c.CollectionInterval <= 0 {
@@ -471,6 +396,17 @@ func (c *Config) validate() error {
 		return fmt.Errorf("vm_id cannot be determined: dmidecode failed to return a valid UUID. Please set vm_id manually in config.yaml or use SC_VM_ID environment variable")
 	}
 
+	hostPaths := []struct{ name, path string }{
+		{"procfs_path", c.ProcfsPath},
+		{"sysfs_path", c.SysfsPath},
+		{"rootfs_path", c.RootfsPath},
+	}
+	for _, hostPath := range hostPaths {
+		if !filepath.IsAbs(hostPath.path) {
+			return fmt.Errorf("%s must be an absolute path: %q", hostPath.name, hostPath.path)
+		}
+	}
+
 	if c.MaxRetries < 0 {
 		return fmt.Errorf("max_retries cannot be negative")
 	}
@@ -492,6 +428,16 @@ func (c *Config) validate() error {
 		return fmt.Errorf("invalid log_level: %s", c.LogLevel)
 	}
 
+	if err := c.Collectors.validate(); err != nil {
+		return err
+	}
+
+	for _, name := range RegisteredCollectors() {
+		if entry, ok := c.Collectors.Entries[name]; ok && entry.Interval < 0 {
+			return fmt.Errorf("interval of collector %s must not be negative", name)
+		}
+	}
+
 	// Validate at least one collector is enabled
 	if !c.hasEnabledCollectors() {
 		return fmt.Errorf("at least one collector must be enabled")
@@ -502,13 +448,40 @@ func (c *Config) validate() error {
 
 // hasEnabledCollectors checks if at least one collector is enabled
 func (c *Config) hasEnabledCollectors() bool {
-	collectors := c.Collectors
-	return collectors.Processes || collectors.CPU || collectors.CPUFreq || collectors.LoadAvg ||
-		collectors.Memory || collectors.VMStat || collectors.Disk || collectors.DiskStats ||
-		collectors.Filesystem || collectors.Network || collectors.NetDev || collectors.NetStat ||
-		collectors.Sockstat || collectors.Uname || collectors.Time || collectors.Uptime ||
-		collectors.Entropy || collectors.Interrupts || collectors.Thermal || collectors.Pressure ||
-		collectors.Schedstat
+	for _, entry := range c.Collectors.Entries {
+		if entry.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the options of each enabled collector
+func (cc *CollectorConfig) validate() error {
+	if cc.Timeout <= 0 {
+		return fmt.Errorf("collectors timeout must be positive")
+	}
+
+	names := make([]string, 0, len(cc.Entries))
+	for name := range cc.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		entry := cc.Entries[name]
+		if _, ok := collectorSpecs[name]; !ok {
+			return fmt.Errorf("unknown collector %s", name)
+		}
+		if !entry.Enabled {
+			continue
+		}
+		if err := entry.Options.Validate(); err != nil {
+			return fmt.Errorf("invalid options of collector %s: %w", name, err)
+		}
+	}
+
+	return nil
 }
 
 // String returns a string representation of the config (excluding sensitive data)
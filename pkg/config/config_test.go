@@ -38,7 +38,7 @@ func TestDefaultConfig(t *testing.T) {
 	}
 	assert.NotEmpty(t, cfg.VMID)
 	assert.NotNil(t, cfg.Labels)
-	assert.True(t, cfg.Collectors.Processes)
+	assert.True(t, cfg.Collectors.Enabled("processes"))
 	assert.Equal(t, "info", cfg.LogLevel)
 	assert.Equal(t, 3, cfg.MaxRetries)
 	assert.Equal(t, 5*time.Second, cfg.RetryInterval)
@@ -82,7 +82,7 @@ func TestLoad_DefaultsOnly(t *testing.T) {
 	assert.Equal(t, "info", cfg.LogLevel)
 	assert.Equal(t, 3, cfg.MaxRetries)
 	assert.Equal(t, 5*time.Second, cfg.RetryInterval)
-	assert.Equal(t, true, cfg.Collectors.Processes)
+	assert.True(t, cfg.Collectors.Enabled("processes"))
 	assert.Equal(t, "test-vm-default", cfg.VMID)
 }
 
@@ -111,6 +111,10 @@ func TestLoad_FromEnvironment(t *testing.T) {
 		"SC_RETRY_INTERVAL":      "10s",
 		"SC_LABELS":              "env=test,region=us-west-2",
 		"SC_COLLECTOR_PROCESSES": "false",
+		"SC_COLLECTOR_INTERVALS": "uname=1h,filesystem=2m",
+		"SC_PROCFS_PATH":         "/host/proc",
+		"SC_SYSFS_PATH":          "/host/sys",
+		"SC_ROOTFS_PATH":         "/host/root",
 	}
 
 	for key, value := range testEnvVars {
@@ -129,7 +133,12 @@ func TestLoad_FromEnvironment(t *testing.T) {
 	assert.Equal(t, 10*time.Second, cfg.RetryInterval)
 	assert.Equal(t, "test", cfg.Labels["env"])
 	assert.Equal(t, "us-west-2", cfg.Labels["region"])
-	assert.False(t, cfg.Collectors.Processes)
+	assert.False(t, cfg.Collectors.Enabled("processes"))
+	assert.Equal(t, time.Hour, cfg.Collectors.Entries["uname"].Interval)
+	assert.Equal(t, 2*time.Minute, cfg.Collectors.Entries["filesystem"].Interval)
+	assert.Equal(t, "/host/proc", cfg.ProcfsPath)
+	assert.Equal(t, "/host/sys", cfg.SysfsPath)
+	assert.Equal(t, "/host/root", cfg.RootfsPath)
 	assert.Equal(t, "http://test-env.example.com/metadata/v1/auth-token", cfg.MetadataServiceEndpoint)
 }
 
@@ -210,6 +219,12 @@ func TestParseLabels(t *testing.T) {
 	}
 }
 
+func TestParseList(t *testing.T) {
+	assert.Equal(t, []string{"nginx", "postgres"}, parseList("nginx, postgres"))
+	assert.Equal(t, []string{"nginx"}, parseList(" nginx ,, "))
+	assert.Nil(t, parseList(""))
+}
+
 func TestValidate(t *testing.T) {
 	// Create temporary agent.yaml for DefaultConfig
 	tmpDir := t.TempDir()
@@ -246,6 +261,19 @@ func TestValidate(t *testing.T) {
 	assert.Contains(t, err.Error(), "http_timeout must be positive")
 	
 	
+	// Test relative host paths
+	invalidConfig = *validConfig
+	invalidConfig.ProcfsPath = "host/proc"
+	err = invalidConfig.validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "procfs_path must be an absolute path")
+
+	invalidConfig = *validConfig
+	invalidConfig.RootfsPath = ""
+	err = invalidConfig.validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rootfs_path must be an absolute path")
+
 	// Test empty VM ID
 	invalidConfig = *validConfig
 	invalidConfig.VMID = ""
@@ -274,6 +302,45 @@ func TestValidate(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid log_level")
 	
+	// Test invalid collector options
+	invalidConfig = *validConfig
+	invalidConfig.Collectors = NewCollectorConfig()
+	invalidConfig.Collectors.Entries["process_top"].Options.(*testProcessTopOptions).TopN = 0
+	err = invalidConfig.validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid options of collector process_top")
+
+	// Test invalid collector timeout
+	invalidConfig = *validConfig
+	invalidConfig.Collectors.Timeout = 0
+	err = invalidConfig.validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "collectors timeout must be positive")
+
+	// Test collector interval shorter than the collection interval, which is allowed
+	invalidConfig = *validConfig
+	invalidConfig.Collectors = NewCollectorConfig()
+	invalidConfig.Collectors.Entries["cpu"].Interval = validConfig.CollectionInterval / 2
+	assert.NoError(t, invalidConfig.validate())
+
+	// Test negative collector interval
+	invalidConfig = *validConfig
+	invalidConfig.Collectors = NewCollectorConfig()
+	invalidConfig.Collectors.Entries["cpu"].Interval = -time.Second
+	err = invalidConfig.validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "interval of collector cpu must not be negative")
+
+	// Test no collectors enabled
+	invalidConfig = *validConfig
+	invalidConfig.Collectors = NewCollectorConfig()
+	for _, entry := range invalidConfig.Collectors.Entries {
+		entry.Enabled = false
+	}
+	err = invalidConfig.validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "at least one collector must be enabled")
+
 	// Test valid log levels
 	validLogLevels := []string{"debug", "info", "warn", "error", "fatal", "panic"}
 	for _, level := range validLogLevels {
@@ -346,7 +413,7 @@ func TestLoadFromEnv_InvalidValues(t *testing.T) {
 	assert.Equal(t, 30*time.Second, cfg.HTTPTimeout)
 	assert.Equal(t, 3, cfg.MaxRetries)
 	assert.Equal(t, 5*time.Second, cfg.RetryInterval)
-	assert.Equal(t, true, cfg.Collectors.Processes)
+	assert.True(t, cfg.Collectors.Enabled("processes"))
 	assert.Equal(t, "test-vm-invalid", cfg.VMID)
 	assert.Equal(t, "http://test.example.com/metadata/v1/auth-token", cfg.MetadataServiceEndpoint)
 }
@@ -370,7 +437,7 @@ func TestCollectorConfig(t *testing.T) {
 	cfg.VMID = "test-vm-collector" // Set test VM ID
 
 	// Test default collector config
-	assert.True(t, cfg.Collectors.Processes)
+	assert.True(t, cfg.Collectors.Enabled("processes"))
 
 	// Test collector config from environment
 	clearEnvVars()
@@ -381,10 +448,33 @@ func TestCollectorConfig(t *testing.T) {
 
 	cfg, err := Load()
 	require.NoError(t, err)
-	assert.False(t, cfg.Collectors.Processes)
+	assert.False(t, cfg.Collectors.Enabled("processes"))
 	assert.Equal(t, "test-vm-collector-env", cfg.VMID)
 }
 
+func TestTickInterval(t *testing.T) {
+	cfg := Config{CollectionInterval: 30 * time.Second, Collectors: NewCollectorConfig()}
+	cfg.Collectors.Entries["uname"].Interval = time.Hour
+
+	// Longer intervals keep collection on collection_interval
+	assert.Equal(t, 30*time.Second, cfg.TickInterval())
+	cfg.scheduleCollectors()
+	assert.Equal(t, time.Duration(0), cfg.Collectors.Entries["processes"].Interval)
+
+	// Disabled collectors do not shorten the tick
+	cfg.Collectors.Entries["cpu_freq"].Interval = 5 * time.Second
+	assert.Equal(t, 30*time.Second, cfg.TickInterval())
+
+	// A shorter interval ticks faster, while the other collectors keep
+	// running every collection_interval
+	cfg.Collectors.Entries["cpu"].Interval = 10 * time.Second
+	assert.Equal(t, 10*time.Second, cfg.TickInterval())
+	cfg.scheduleCollectors()
+	assert.Equal(t, 10*time.Second, cfg.Collectors.Entries["cpu"].Interval)
+	assert.Equal(t, 30*time.Second, cfg.Collectors.Entries["processes"].Interval)
+	assert.Equal(t, time.Hour, cfg.Collectors.Entries["uname"].Interval)
+}
+
 // Helper functions
 
 func clearEnvVars() {
@@ -398,6 +488,10 @@ func clearEnvVars() {
 		"SC_RETRY_INTERVAL",
 		"SC_LABELS",
 		"SC_COLLECTOR_PROCESSES",
+		"SC_COLLECTOR_INTERVALS",
+		"SC_PROCFS_PATH",
+		"SC_SYSFS_PATH",
+		"SC_ROOTFS_PATH",
 	}
 	
 	for _, envVar := range envVars {
@@ -267,12 +267,21 @@ func (p *Processor) WriteDiagnostics(ctx context.Context) error {
 
 	// Get collector status if available
 	collectorStatus := make(map[string]bool)
+	collectorDetails := make(map[string]tsclient.CollectorDetails)
 	if systemCollector, ok := p.collector.(*collector.SystemCollector); ok {
-		collectorStatus = systemCollector.GetEnabledCollectors()
+		for name, status := range systemCollector.GetEnabledCollectors() {
+			collectorStatus[name] = status.Enabled
+			collectorDetails[name] = tsclient.CollectorDetails{
+				Success:         status.Success,
+				TimedOut:        status.TimedOut,
+				DurationSeconds: status.Duration.Seconds(),
+				LastError:       status.LastError,
+			}
+		}
 	}
 
 	// Send diagnostics
-	if err := p.writer.WriteDiagnostics(ctx, p.getAgentID(), status, p.lastError, collectorStatus, authToken); err != nil {
+	if err := p.writer.WriteDiagnostics(ctx, p.getAgentID(), status, p.lastError, collectorStatus, collectorDetails, authToken); err != nil {
 		p.logger.Error("Failed to write diagnostics", zap.Error(err))
 		return fmt.Errorf("failed to write diagnostics: %w", err)
 	}